@@ -0,0 +1,498 @@
+package ecg_test
+
+import (
+    "context"
+    "encoding/xml"
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/beevik/etree"
+    "io/ioutil"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+)
+
+const advertXML = `<?xml version="1.0" encoding="UTF-8"?>
+<ad:ad xmlns:ad="http://www.ebayclassifiedsgroup.com/schema/ad/v1" id="123456">
+    <ad:title>Bicycle</ad:title>
+</ad:ad>`
+
+const apiErrorXML = `<?xml version="1.0" encoding="UTF-8"?>
+<api-base-error xmlns="http://www.ebayclassifiedsgroup.com/schema/types/v1" http-status-code="400">
+    <api-errors>
+        <api-error>
+            <message>Ad could not be posted</message>
+            <error-code>VALIDATION</error-code>
+        </api-error>
+    </api-errors>
+    <api-field-errors>
+        <api-field-error>
+            <field>title</field>
+            <message>Title is too short</message>
+            <error-code>TOO_SHORT</error-code>
+        </api-field-error>
+        <api-field-error>
+            <field>price.amount</field>
+            <message>Price is missing</message>
+            <localized-message>Preis fehlt</localized-message>
+        </api-field-error>
+    </api-field-errors>
+</api-base-error>`
+
+func TestAgentRequestEndpointContext(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "password" {
+            w.WriteHeader(http.StatusUnauthorized)
+            return
+        }
+
+        if r.Header.Get(ecg.HeaderAuthenticateDevice) != "device" || r.Header.Get(ecg.HeaderAuthenticateUser) != "" {
+            w.WriteHeader(http.StatusForbidden)
+            return
+        }
+
+        w.Write([]byte(advertXML))
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{
+        Endpoint:   server.URL,
+        HTTPClient: server.Client(),
+        ECGAuthorization: &ecg.Authorization{
+            Username: "user",
+            Password: "password",
+        },
+        ECGAuthentication: &ecg.Authentication{
+            AuthenticateUser:   "user",
+            AuthenticateDevice: "device",
+        },
+    }
+
+    doc, err := agent.RequestEndpointContext(context.Background(), "/ads/123456")
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    if root := doc.Root(); root == nil || root.Tag != "ad" {
+        t.Fatalf("unexpected document root: %v", root)
+    }
+}
+
+func TestAgentDo(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.Method {
+        case "POST":
+            body, _ := ioutil.ReadAll(r.Body)
+
+            if r.Header.Get("Content-Type") != "application/xml; charset=utf-8" || !strings.Contains(string(body), "<title>Bicycle</title>") {
+                w.WriteHeader(http.StatusBadRequest)
+                w.Write([]byte(`<api-base-error><message>bad request</message></api-base-error>`))
+                return
+            }
+
+            w.WriteHeader(http.StatusCreated)
+            w.Write([]byte(advertXML))
+        case "DELETE":
+            w.WriteHeader(http.StatusNoContent)
+        default:
+            w.WriteHeader(http.StatusMethodNotAllowed)
+            w.Write([]byte(`<api-base-error><message>method not allowed</message></api-base-error>`))
+        }
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL}
+
+    type draft struct {
+        XMLName xml.Name `xml:"ad"`
+        Title   string   `xml:"title"`
+    }
+
+    if doc, err := agent.Do("POST", "/ads", draft{Title: "Bicycle"}); err != nil || doc == nil {
+        t.Fatalf("unexpected POST result: %v %v", doc, err)
+    }
+
+    if doc, err := agent.Do("DELETE", "/users/me/ads/123456", nil); err != nil || doc != nil {
+        t.Fatalf("unexpected DELETE result: %v %v", doc, err)
+    }
+
+    var apiErr *ecg.APIError
+
+    if _, err := agent.Do("PUT", "/ads/123456", nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusMethodNotAllowed || apiErr.Message != "method not allowed" {
+        t.Fatalf("expected PUT to fail with 405, got %v", err)
+    }
+}
+
+func TestAgentRetry(t *testing.T) {
+    var requests int
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        requests++
+
+        if requests < 3 {
+            w.Header().Set("Retry-After", "0")
+            w.WriteHeader(http.StatusServiceUnavailable)
+            w.Write([]byte(`<api-base-error><message>try again</message></api-base-error>`))
+            return
+        }
+
+        w.Write([]byte(advertXML))
+    }))
+    defer server.Close()
+
+    policy := ecg.DefaultRetryPolicy
+    policy.BaseDelay = time.Millisecond
+
+    agent := ecg.Agent{Endpoint: server.URL, Retry: &policy}
+
+    if _, err := agent.RequestEndpointContext(context.Background(), "/ads/123456"); err != nil || requests != 3 {
+        t.Fatalf("expected success after 3 requests, got %d requests and %v", requests, err)
+    }
+
+    requests = 0
+
+    var apiErr *ecg.APIError
+
+    if _, err := agent.Do("POST", "/ads", advertXML); !errors.As(err, &apiErr) || apiErr.Attempts != 1 || requests != 1 {
+        t.Fatalf("expected POST not to be retried, got %d requests and %v", requests, err)
+    }
+
+    requests = 0
+    policy.MaxAttempts = 2
+
+    if _, err := agent.RequestEndpointContext(context.Background(), "/ads/123456"); !errors.As(err, &apiErr) || apiErr.Attempts != 2 || apiErr.StatusCode != 503 {
+        t.Fatalf("expected failure after 2 attempts, got %v", err)
+    }
+}
+
+func TestAgentRateLimiter(t *testing.T) {
+    var mu sync.Mutex
+    var inFlight, maxInFlight int
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        inFlight++
+        if inFlight > maxInFlight {
+            maxInFlight = inFlight
+        }
+        mu.Unlock()
+
+        time.Sleep(5 * time.Millisecond)
+        w.Write([]byte(advertXML))
+
+        mu.Lock()
+        inFlight--
+        mu.Unlock()
+    }))
+    defer server.Close()
+
+    limiter := ecg.NewRateLimiter(ecg.Limit{MaxInFlight: 2}, map[ecg.EndpointClass]ecg.Limit{
+        ecg.EndpointClassAdvert: {Rate: 200, Burst: 1},
+    })
+
+    agent := ecg.Agent{Endpoint: server.URL, RateLimiter: limiter}
+
+    var wg sync.WaitGroup
+    for i := 0; i < 8; i++ {
+        wg.Add(1)
+
+        go func() {
+            defer wg.Done()
+
+            if _, err := agent.RequestAdvert(context.Background(), 123456); err != nil {
+                t.Errorf("unexpected error: %v", err)
+            }
+        }()
+    }
+    wg.Wait()
+
+    if maxInFlight > 2 {
+        t.Errorf("expected at most 2 requests in flight, got %d", maxInFlight)
+    }
+
+    stats := limiter.Stats()[ecg.EndpointClassAdvert]
+    if stats.Requests != 8 || stats.Delayed == 0 || stats.TotalWait <= 0 {
+        t.Errorf("unexpected limiter stats: %+v", stats)
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    if _, err := agent.RequestAdvert(ctx, 123456); !errors.Is(err, ecg.ErrTimeout) || !errors.Is(err, context.Canceled) {
+        t.Errorf("expected cancelled request to fail, got %v", err)
+    }
+}
+
+func TestAgentRateLimiterSigning(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(advertXML))
+    }))
+    defer server.Close()
+
+    var signedAt []time.Time
+
+    limiter := ecg.NewRateLimiter(ecg.Limit{}, map[ecg.EndpointClass]ecg.Limit{
+        ecg.EndpointClassAdvert: {Rate: 10, Burst: 1},
+    })
+
+    agent := ecg.Agent{Endpoint: server.URL, RateLimiter: limiter, ECGAuthentication: &ecg.Authentication{
+        AuthenticateDevice: "device",
+        Signer: func(scope ecg.AuthScope, credential string, method string, url string) (string, error) {
+            signedAt = append(signedAt, time.Now())
+            return credential, nil
+        },
+    }}
+
+    start := time.Now()
+    for i := 0; i < 2; i++ {
+        if _, err := agent.RequestAdvert(context.Background(), 123456); err != nil {
+            t.Fatalf("unexpected error: %v", err)
+        }
+    }
+
+    // the second request waits about 100ms for a token and must only be signed afterwards
+    if len(signedAt) != 2 || signedAt[1].Sub(start) < 50 * time.Millisecond {
+        t.Errorf("expected the delayed request to be signed after waiting, signed at %v", signedAt)
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    if _, err := agent.RequestAdvert(ctx, 123456); !errors.Is(err, ecg.ErrTimeout) || len(signedAt) != 2 {
+        t.Errorf("expected request aborted while waiting not to be signed, got %v after %d signatures", err, len(signedAt))
+    }
+}
+
+func TestAgentErrors(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/ads/1":
+            w.WriteHeader(http.StatusNotFound)
+            w.Write([]byte(`<api-base-error><message>ad not found</message></api-base-error>`))
+        case "/ads/2":
+            w.Header().Set("Retry-After", "7")
+            w.WriteHeader(http.StatusTooManyRequests)
+        case "/ads/3":
+            w.Write([]byte(`<ad:ad`))
+        case "/ads/4":
+            w.WriteHeader(http.StatusUnauthorized)
+        case "/ads/5":
+            w.WriteHeader(http.StatusBadRequest)
+            w.Write([]byte(apiErrorXML))
+        }
+    }))
+
+    agent := ecg.Agent{Endpoint: server.URL}
+    ctx := context.Background()
+
+    var notFoundErr *ecg.NotFoundError
+    if _, err := agent.RequestAdvert(ctx, 1); !errors.As(err, &notFoundErr) || !errors.Is(err, ecg.ErrAPI) || notFoundErr.Message != "ad not found" {
+        t.Errorf("expected not found error, got %v", err)
+    }
+
+    var rateLimitErr *ecg.RateLimitError
+    if _, err := agent.RequestAdvert(ctx, 2); !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 7 * time.Second {
+        t.Errorf("expected rate limit error, got %v", err)
+    }
+
+    var decodeErr *ecg.DecodeError
+    if _, err := agent.RequestAdvert(ctx, 3); !errors.As(err, &decodeErr) || string(decodeErr.Body) != `<ad:ad` {
+        t.Errorf("expected decode error, got %v", err)
+    }
+
+    if _, err := agent.RequestAdvert(ctx, 4); !errors.Is(err, ecg.ErrUnauthorized) {
+        t.Errorf("expected auth error, got %v", err)
+    }
+
+    var apiErr *ecg.APIError
+    if _, err := agent.RequestAdvert(ctx, 5); !errors.As(err, &apiErr) || apiErr.Code != "VALIDATION" || apiErr.Message != "Ad could not be posted" {
+        t.Errorf("expected API error, got %v", err)
+    } else if fields := apiErr.FieldErrors(); len(fields) != 2 || fields[1].Field != "price.amount" || fields[1].LocalizedMessage != "Preis fehlt" {
+        t.Errorf("unexpected field errors: %+v", fields)
+    }
+
+    server.Close()
+
+    if _, err := agent.RequestAdvert(ctx, 1); !errors.Is(err, ecg.ErrTransport) {
+        t.Errorf("expected transport error, got %v", err)
+    }
+}
+
+func TestAgentGetAdvert(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch r.URL.Path {
+        case "/ads/123456":
+            w.Write([]byte(advertXML))
+        case "/ads/1":
+            w.Write([]byte(`<ad:ads xmlns:ad="http://www.ebayclassifiedsgroup.com/schema/ad/v1"/>`))
+        default:
+            w.WriteHeader(http.StatusNotFound)
+        }
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL}
+
+    advert, report, err := agent.GetAdvert(context.Background(), 123456)
+    if err != nil || advert == nil || advert.ID != 123456 || advert.Title != "Bicycle" {
+        t.Fatalf("unexpected advert %+v: %v", advert, err)
+    }
+
+    if missing := report.Filter(parsers.SeverityMissingOptional); len(missing) < 1 || missing[0].AdvertID != 123456 {
+        t.Errorf("expected warnings for missing optional fields, got %v", report)
+    }
+
+    _, _, err = agent.GetAdvert(context.Background(), 1)
+
+    var parseErr *ecg.ParseFailureError
+    if !errors.Is(err, ecg.ErrParse) || !errors.As(err, &parseErr) || !errors.Is(err, parsers.ErrUnexpectedResponse) {
+        t.Errorf("expected parse error, got %v", err)
+    }
+
+    if _, _, err := agent.GetAdvert(context.Background(), 2); !errors.Is(err, ecg.ErrNotFound) {
+        t.Errorf("expected not found error, got %v", err)
+    }
+}
+
+type fakeParser struct{}
+
+func (fakeParser) ParseAdvert(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return doc.Root().Tag, &parsers.ParseReport{}
+}
+
+func (fakeParser) ParseAdvertList(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return nil, &parsers.ParseReport{}
+}
+
+func (fakeParser) ParseCategories(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return nil, &parsers.ParseReport{}
+}
+
+func (fakeParser) ParseError(doc *etree.Document) *parsers.ErrorDocument {
+    return parsers.ParseErrorDocument(doc)
+}
+
+func TestAgentBuiltinMarketplaces(t *testing.T) {
+    for _, marketplace := range []string{"au", "de", "ca"} {
+        if _, ok := parsers.Lookup(marketplace); !ok {
+            t.Errorf("expected built-in parser %q to be registered, got %v", marketplace, parsers.Marketplaces())
+        }
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        marketplace := strings.Split(r.URL.Path, "/")[1] // e.g. /de/ads/1
+        http.ServeFile(w, r, "parsers/" + marketplace + "/testdata/advert.xml")
+    }))
+    defer server.Close()
+
+    german := ecg.Agent{Endpoint: server.URL + "/de"}.German()
+
+    if advert, _, err := german.GetAdvert(context.Background(), 1); err != nil || advert.ID != 1234567890 {
+        t.Errorf("expected German advert, got %+v: %v", advert, err)
+    }
+
+    canadian := ecg.Agent{Endpoint: server.URL + "/ca"}.Canadian()
+
+    if advert, _, err := canadian.GetAdvert(context.Background(), 1); err != nil || advert.ID != 1423456789 {
+        t.Errorf("expected Canadian advert, got %+v: %v", advert, err)
+    }
+
+    agent := ecg.Agent{Endpoint: server.URL + "/de", Marketplace: "de"}
+
+    if _, _, err := agent.GetAdvert(context.Background(), 1); !errors.Is(err, ecg.ErrModelMismatch) {
+        t.Errorf("expected model mismatch of the Australian typed API, got %v", err)
+    }
+}
+
+func TestAgentMarketplace(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(advertXML))
+    }))
+    defer server.Close()
+
+    parsers.Register("test", fakeParser{})
+
+    agent := ecg.Agent{Endpoint: server.URL, Marketplace: "test"}
+
+    if model, _, err := agent.FetchAdvert(context.Background(), 123456); err != nil || model != "ad" {
+        t.Errorf("expected model of the test parser, got %v: %v", model, err)
+    }
+
+    if _, _, err := agent.GetAdvert(context.Background(), 123456); !errors.Is(err, ecg.ErrModelMismatch) {
+        t.Errorf("expected model mismatch, got %v", err)
+    }
+
+    if _, _, err := agent.GetLocations(context.Background()); !errors.Is(err, ecg.ErrNotSupported) {
+        t.Errorf("expected locations not to be supported, got %v", err)
+    }
+
+    agent.Marketplace = "xx"
+
+    if _, _, err := agent.FetchAdvert(context.Background(), 123456); !errors.Is(err, ecg.ErrUnknownMarketplace) {
+        t.Errorf("expected unknown marketplace, got %v", err)
+    }
+}
+
+// newSlowServer responds with an advert after the delay given in milliseconds by the `delay` query parameter
+func newSlowServer() *httptest.Server {
+    return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        delay, _ := time.ParseDuration(r.URL.Query().Get("delay") + "ms")
+
+        select {
+        case <-time.After(delay):
+            w.Write([]byte(advertXML))
+        case <-r.Context().Done():
+        }
+    }))
+}
+
+func TestAgentContextCancellation(t *testing.T) {
+    server := newSlowServer()
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL}
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+
+    var timeoutErr *ecg.TimeoutError
+    if _, err := agent.RequestEndpointContext(ctx, "/ads/1?delay=0"); !errors.As(err, &timeoutErr) || !errors.Is(err, context.Canceled) || !errors.Is(err, ecg.ErrTimeout) {
+        t.Errorf("expected cancelled context to abort the request, got %v", err)
+    }
+
+    ctx, cancel = context.WithTimeout(context.Background(), 50 * time.Millisecond)
+    defer cancel()
+
+    start := time.Now()
+    if _, err := agent.RequestEndpointContext(ctx, "/ads/1?delay=2000"); !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+        t.Errorf("expected passed deadline to abort the request, got %v", err)
+    }
+
+    if elapsed := time.Since(start); elapsed > time.Second {
+        t.Errorf("expected the request to be aborted at the deadline, took %v", elapsed)
+    }
+}
+
+func TestAgentRequestEndpointMilliseconds(t *testing.T) {
+    server := newSlowServer()
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL}
+
+    if _, err := agent.RequestEndpoint("/ads/1?delay=20", 1000); err != nil { // would time out if taken as nanoseconds
+        t.Errorf("expected the timeout to be taken as milliseconds, got %v", err)
+    }
+
+    start := time.Now()
+    if _, err := agent.RequestEndpoint("/ads/1?delay=2000", 50); !errors.Is(err, ecg.ErrTimeout) {
+        t.Errorf("expected the request to time out, got %v", err)
+    }
+
+    if elapsed := time.Since(start); elapsed > time.Second {
+        t.Errorf("expected the request to time out after 50 milliseconds, took %v", elapsed)
+    }
+}
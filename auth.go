@@ -0,0 +1,111 @@
+package ecg
+
+import (
+    "fmt"
+    "strings"
+)
+
+// ECG authentication headers, one for each authentication scope
+const (
+    HeaderAuthenticateUser   = "X-ECG-Authenticate-User"
+    HeaderAuthenticateAd     = "X-ECG-Authenticate-Ad"
+    HeaderAuthenticateDevice = "X-ECG-Authenticate-Device"
+)
+
+// AuthScope is a set of ECG authentication scopes required by an endpoint
+type AuthScope uint
+
+// Authentication scopes, which can be combined with bitwise OR
+const (
+    AuthScopeDevice AuthScope = 1 << iota // identifies the calling device / installation
+    AuthScopeUser                         // acts on behalf of a user
+    AuthScopeAd                           // acts on a specific advertisement
+)
+
+// AuthScopeNone requires no ECG authentication
+const AuthScopeNone AuthScope = 0
+
+// Has reports whether all scopes in `other` are included
+func (scope AuthScope) Has(other AuthScope) bool {
+    return scope&other == other
+}
+
+// Signer computes the header value sent for an authentication scope from the configured credential,
+// the HTTP method and the full request URL. The returned value replaces the credential in the header.
+type Signer func(scope AuthScope, credential string, method string, url string) (string, error)
+
+// ScopeResolver decides which authentication scopes are sent for a request
+type ScopeResolver func(method string, path string) AuthScope
+
+// EndpointAuthScope is the default ScopeResolver.
+// The device scope is sent with every request, the user scope with requests under `/users`
+// and with any write request, and the ad scope with write requests addressing a specific ad.
+func EndpointAuthScope(method string, path string) AuthScope {
+    scope := AuthScopeDevice
+    isWrite := method != "" && method != "GET" && method != "HEAD"
+
+    if i := strings.IndexAny(path, "?#"); i >= 0 { // ignore query and fragment
+        path = path[:i]
+    }
+
+    segments := strings.Split(strings.Trim(path, "/"), "/")
+
+    if segments[0] == "users" || isWrite {
+        scope |= AuthScopeUser
+    }
+
+    if isWrite {
+        for i := 0; i < len(segments)-1; i++ {
+            if segments[i] == "ads" && segments[i+1] != "" {
+                scope |= AuthScopeAd
+                break
+            }
+        }
+    }
+
+    return scope
+}
+
+// scope returns the authentication scopes sent for a request, as decided by the ScopeResolver if one is set
+func (auth Authentication) scope(method string, path string) AuthScope {
+    if auth.Scope != nil {
+        return auth.Scope(method, path)
+    }
+
+    return EndpointAuthScope(method, path)
+}
+
+// headers builds the ECG authentication headers of a request, signing credentials if a Signer is set
+func (auth Authentication) headers(method string, url string, path string) (map[string]string, error) {
+    scope := auth.scope(method, path)
+    headers := make(map[string]string)
+
+    for _, entry := range []struct {
+        scope      AuthScope
+        header     string
+        credential string
+    }{
+        {AuthScopeUser, HeaderAuthenticateUser, auth.AuthenticateUser},
+        {AuthScopeAd, HeaderAuthenticateAd, auth.AuthenticateAd},
+        {AuthScopeDevice, HeaderAuthenticateDevice, auth.AuthenticateDevice},
+    } {
+        if !scope.Has(entry.scope) || entry.credential == "" {
+            continue
+        }
+
+        value := entry.credential
+
+        if auth.Signer != nil {
+            signed, err := auth.Signer(entry.scope, entry.credential, method, url)
+            if err != nil {
+                return nil, fmt.Errorf("unable to sign %s: %v", entry.header, err)
+            }
+
+            value = signed
+        }
+
+        headers[entry.header] = value
+    }
+
+    return headers, nil
+}
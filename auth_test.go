@@ -0,0 +1,95 @@
+package ecg_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+)
+
+func TestEndpointAuthScope(t *testing.T) {
+    cases := []struct {
+        method string
+        path   string
+        scope  ecg.AuthScope
+    }{
+        {"GET", "/ads/123456", ecg.AuthScopeDevice},
+        {"GET", "/users/me/ads?page=1", ecg.AuthScopeDevice | ecg.AuthScopeUser},
+        {"POST", "/ads", ecg.AuthScopeDevice | ecg.AuthScopeUser},
+        {"PUT", "/ads/123456", ecg.AuthScopeDevice | ecg.AuthScopeUser | ecg.AuthScopeAd},
+        {"DELETE", "/users/me/ads/123456", ecg.AuthScopeDevice | ecg.AuthScopeUser | ecg.AuthScopeAd},
+    }
+
+    for _, c := range cases {
+        if scope := ecg.EndpointAuthScope(c.method, c.path); scope != c.scope {
+            t.Errorf("%s %s: expected scope %d, got %d", c.method, c.path, c.scope, scope)
+        }
+    }
+}
+
+func TestAuthenticationSigner(t *testing.T) {
+    headers := make(chan http.Header, 1)
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        headers <- r.Header
+        w.Write([]byte(advertXML))
+    }))
+    defer server.Close()
+
+    auth := &ecg.Authentication{
+        AuthenticateUser:   "user",
+        AuthenticateAd:     "ad",
+        AuthenticateDevice: "device",
+        Signer: func(scope ecg.AuthScope, credential string, method string, url string) (string, error) {
+            return fmt.Sprintf("%d:%s:%s:%s", scope, credential, method, url), nil
+        },
+    }
+
+    agent := ecg.Agent{Endpoint: server.URL, ECGAuthentication: auth}
+
+    if _, err := agent.RequestEndpointContext(context.Background(), "/users/me/ads"); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    h := <-headers
+    if user := h.Get(ecg.HeaderAuthenticateUser); user != fmt.Sprintf("%d:user:GET:%s/users/me/ads", ecg.AuthScopeUser, server.URL) {
+        t.Errorf("unexpected signed user header %q", user)
+    }
+
+    if device := h.Get(ecg.HeaderAuthenticateDevice); device != fmt.Sprintf("%d:device:GET:%s/users/me/ads", ecg.AuthScopeDevice, server.URL) {
+        t.Errorf("unexpected signed device header %q", device)
+    }
+
+    if ad := h.Get(ecg.HeaderAuthenticateAd); ad != "" {
+        t.Errorf("expected no ad header for a read request, got %q", ad)
+    }
+
+    auth.Scope = func(method string, path string) ecg.AuthScope {
+        return ecg.AuthScopeAd
+    }
+
+    if _, err := agent.RequestEndpointContext(context.Background(), "/ads/123456"); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    if h := <-headers; h.Get(ecg.HeaderAuthenticateAd) == "" || h.Get(ecg.HeaderAuthenticateDevice) != "" {
+        t.Errorf("expected only the scopes of the resolver, got %v", h)
+    }
+
+    auth.Signer = func(scope ecg.AuthScope, credential string, method string, url string) (string, error) {
+        return "", errors.New("no signing key")
+    }
+
+    if _, err := agent.RequestEndpointContext(context.Background(), "/ads/123456"); !errors.Is(err, ecg.ErrUnauthorized) {
+        t.Errorf("expected signing failure to be an authentication error, got %v", err)
+    }
+
+    select {
+    case <-headers:
+        t.Errorf("expected the request not to be sent without signed headers")
+    default:
+    }
+}
@@ -0,0 +1,170 @@
+package ecg
+
+import (
+    "context"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    auparser "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    aumodels "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+    "reflect"
+)
+
+// DefaultMarketplace is the marketplace of agents without one
+const DefaultMarketplace = auparser.Marketplace
+
+// parser returns the parser registered for the marketplace of the agent
+func (agent Agent) parser() (parsers.Parser, error) {
+    marketplace := agent.Marketplace
+    if marketplace == "" {
+        marketplace = DefaultMarketplace
+    }
+
+    parser, ok := parsers.Lookup(marketplace)
+    if !ok {
+        return nil, fmt.Errorf("%w %q, registered marketplaces are %v", ErrUnknownMarketplace, marketplace, parsers.Marketplaces())
+    }
+
+    return parser, nil
+}
+
+// parse applies a parser method to a document, turning a fatal parsing result into an error
+func (agent Agent) parse(doc *etree.Document, parse func(parsers.Parser, *etree.Document) (interface{}, *parsers.ParseReport)) (interface{}, *parsers.ParseReport, error) {
+    parser, err := agent.parser()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    model, report := parse(parser, doc)
+    if report.IsFatal() || model == nil {
+        return nil, nil, &ParseFailureError{Report: report}
+    }
+
+    return model, report, nil
+}
+
+// typed stores the model of a Fetch method in the variable target points to, e.g. a `*aumodels.Advert`,
+// failing with `ErrModelMismatch` if the parser of the marketplace returned a model of another type
+//
+//     report, err = typed(agent.FetchAdvert(ctx, id))(&advert)
+func typed(model interface{}, report *parsers.ParseReport, err error) func(target interface{}) (*parsers.ParseReport, error) {
+    return func(target interface{}) (*parsers.ParseReport, error) {
+        if err != nil {
+            return nil, err
+        }
+
+        value, variable := reflect.ValueOf(model), reflect.ValueOf(target).Elem()
+        if !value.IsValid() || !value.Type().AssignableTo(variable.Type()) {
+            return nil, fmt.Errorf("%w: %T", ErrModelMismatch, model)
+        }
+
+        variable.Set(value)
+        return report, nil
+    }
+}
+
+// FetchAdvert requests a single advertisement and parses it with the parser of the agent's marketplace.
+func (agent Agent) FetchAdvert(ctx context.Context, id uint) (interface{}, *parsers.ParseReport, error) {
+    doc, err := agent.RequestAdvert(ctx, id)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return agent.parse(doc, parsers.Parser.ParseAdvert)
+}
+
+// FetchAdvertList requests a single page of advertisements matching the query
+// and parses it with the parser of the agent's marketplace.
+func (agent Agent) FetchAdvertList(ctx context.Context, query SearchQuery) (interface{}, *parsers.ParseReport, error) {
+    doc, err := agent.RequestSearch(ctx, query)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return agent.parse(doc, parsers.Parser.ParseAdvertList)
+}
+
+// FetchCategories requests the category tree and parses it with the parser of the agent's marketplace.
+func (agent Agent) FetchCategories(ctx context.Context) (interface{}, *parsers.ParseReport, error) {
+    doc, err := agent.RequestCategories(ctx)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return agent.parse(doc, parsers.Parser.ParseCategories)
+}
+
+// FetchLocations requests the location hierarchy and parses it with the parser of the agent's marketplace,
+// failing with `ErrNotSupported` if the parser does not implement `parsers.LocationParser`.
+func (agent Agent) FetchLocations(ctx context.Context) (interface{}, *parsers.ParseReport, error) {
+    parser, err := agent.parser()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if _, ok := parser.(parsers.LocationParser); !ok {
+        return nil, nil, fmt.Errorf("%w: %T cannot parse locations", ErrNotSupported, parser)
+    }
+
+    doc, err := agent.RequestLocations(ctx)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return agent.parse(doc, func(parser parsers.Parser, doc *etree.Document) (interface{}, *parsers.ParseReport) {
+        return parser.(parsers.LocationParser).ParseLocations(doc)
+    })
+}
+
+// FetchAttributeSchema requests the attribute metadata of a category and parses it with the parser of the agent's marketplace,
+// failing with `ErrNotSupported` if the parser does not implement `parsers.AttributeSchemaParser`.
+func (agent Agent) FetchAttributeSchema(ctx context.Context, categoryID uint) (interface{}, *parsers.ParseReport, error) {
+    parser, err := agent.parser()
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if _, ok := parser.(parsers.AttributeSchemaParser); !ok {
+        return nil, nil, fmt.Errorf("%w: %T cannot parse attribute metadata", ErrNotSupported, parser)
+    }
+
+    doc, err := agent.RequestAttributeMetadata(ctx, categoryID)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    return agent.parse(doc, func(parser parsers.Parser, doc *etree.Document) (interface{}, *parsers.ParseReport) {
+        return parser.(parsers.AttributeSchemaParser).ParseAttributeSchema(doc)
+    })
+}
+
+// GetAdvert requests and parses a single advertisement of the Australian marketplace.
+// Other marketplaces fail with `ErrModelMismatch`, see `Agent.German` and `Agent.Canadian` instead.
+func (agent Agent) GetAdvert(ctx context.Context, id uint) (advert *aumodels.Advert, report *parsers.ParseReport, err error) {
+    report, err = typed(agent.FetchAdvert(ctx, id))(&advert)
+    return advert, report, err
+}
+
+// SearchAdverts requests and parses a single page of Australian advertisements matching the query
+func (agent Agent) SearchAdverts(ctx context.Context, query SearchQuery) (category *aumodels.Category, report *parsers.ParseReport, err error) {
+    report, err = typed(agent.FetchAdvertList(ctx, query))(&category)
+    return category, report, err
+}
+
+// GetCategoryTree requests and parses the Australian categories, returning every top-level category in document order as the root of its own tree.
+func (agent Agent) GetCategoryTree(ctx context.Context) (categories []aumodels.Categories, report *parsers.ParseReport, err error) {
+    report, err = typed(agent.FetchCategories(ctx))(&categories)
+    return categories, report, err
+}
+
+// GetLocations requests and parses the Australian location hierarchy, to be indexed with `aumodels.NewLocationTree`.
+func (agent Agent) GetLocations(ctx context.Context) (locations []aumodels.Location, report *parsers.ParseReport, err error) {
+    report, err = typed(agent.FetchLocations(ctx))(&locations)
+    return locations, report, err
+}
+
+// GetAttributeSchema requests and parses the attribute metadata of an Australian category, against which the attributes of an ad can be validated.
+func (agent Agent) GetAttributeSchema(ctx context.Context, categoryID uint) (schema *aumodels.AttributeSchema, report *parsers.ParseReport, err error) {
+    report, err = typed(agent.FetchAttributeSchema(ctx, categoryID))(&schema)
+    return schema, report, err
+}
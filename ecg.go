@@ -1,27 +1,50 @@
 package ecg
 
 import (
+    "bytes"
+    "context"
+    "encoding/xml"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
     u "github.com/GreenVine/ebay-classifieds-api/utils"
     "github.com/beevik/etree"
-    "github.com/parnurzeal/gorequest"
+    "io"
+    "io/ioutil"
+    "net/http"
+    "strings"
     "time"
 )
 
 // Agent initialises the ECG Agent and pass the API endpoint as base URL.
 // Optionally, you can pass either authentication or authorization or both settings to the agent.
+// Requests are sent through a pooled default transport unless an HTTP client or transport is supplied,
+// e.g. to configure a proxy or TLS, or to point the agent to a test server.
+//
+// Methods parsing a response return the model with a report of non-fatal parsing errors,
+// whereas a failed request or a fatal parsing error yields an error instead. The typed methods of the agent,
+// e.g. `GetAdvert`, return the Australian models only: use `Agent.German` and `Agent.Canadian` for the models
+// of other built-in marketplaces, or the Fetch methods for any registered marketplace.
 //
 // Read more about security settings implemented in the API here: https://api.ebay-kleinanzeigen.de/docs/pages/security.
 type Agent struct {
     Endpoint string // API Endpoint Base URL
     ECGAuthorization *Authorization // HTTP Authorization Header
     ECGAuthentication *Authentication // HTTP Authentication
+    HTTPClient *http.Client // HTTP client used to send requests (optional)
+    Transport http.RoundTripper // HTTP transport used if no client is set (optional, defaults to `DefaultTransport`)
+    Retry *RetryPolicy // Retry policy of failed requests (optional, no retries if unset)
+    RateLimiter *RateLimiter // Rate limiter shared by concurrent requests (optional)
+    Marketplace string // Marketplace code selecting the parser of typed methods (optional, defaults to `DefaultMarketplace`)
 }
 
-// Authentication is ECG authentication settings
+// Authentication is ECG authentication settings.
+// Each credential is sent in its own header whenever the endpoint requires the corresponding scope.
 type Authentication struct {
     AuthenticateUser string
     AuthenticateAd string
     AuthenticateDevice string
+    Signer Signer // computes signed header values from the credentials (optional)
+    Scope ScopeResolver // overrides the scopes sent per endpoint (optional, defaults to `EndpointAuthScope`)
 }
 
 // Authorization is ECG authorization settings
@@ -30,12 +53,6 @@ type Authorization struct {
     Password string
 }
 
-// EndpointErrorResponse is model of erroneous endpoint response
-type EndpointErrorResponse struct {
-    StatusCode  *uint   `json:"code"` // HTTP Status Code
-    Message     *string `json:"message"` // Status Message (optional)
-}
-
 func (agent Agent) hasECGAuthorization() bool {
     return agent.ECGAuthorization != nil
 }
@@ -45,56 +62,233 @@ func (agent Agent) hasECGAuthentication() bool {
 }
 
 // RequestEndpoint requests the API endpoint along with the URL and timeout (in milliseconds) settings
-// ECG Agent will either return a XML document on success, or an `EndpointErrorResponse` type on failure.
+// ECG Agent will either return a XML document on success, or an error on failure (see `DoContext`).
 //
 // A country-specific parser is required to parse the advertisement or category response
-func (agent Agent) RequestEndpoint(url string, timeout time.Duration) (*etree.Document, *EndpointErrorResponse) {
-    http := gorequest.
-        New().
-        Timeout(timeout * time.Millisecond)
+//
+// Deprecated: the timeout is multiplied by `time.Millisecond`, so passing a `time.Duration` such as
+// `2 * time.Second` results in a far longer timeout than intended. Use `RequestEndpointContext` instead.
+func (agent Agent) RequestEndpoint(url string, timeout time.Duration) (*etree.Document, error) {
+    ctx := context.Background()
 
-    if agent.hasECGAuthorization() {
-        http.SetBasicAuth((*agent.ECGAuthorization).Username, (*agent.ECGAuthorization).Password)
+    if timeout > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, timeout * time.Millisecond)
+        defer cancel()
+    }
+
+    return agent.RequestEndpointContext(ctx, url)
+}
+
+// RequestEndpointContext requests the API endpoint along with the URL, honouring the deadline and cancellation of the context.
+// ECG Agent will either return a XML document on success, or an error on failure (see `DoContext`).
+//
+// A country-specific parser is required to parse the advertisement or category response
+func (agent Agent) RequestEndpointContext(ctx context.Context, url string) (*etree.Document, error) {
+    return agent.DoContext(ctx, "GET", url, nil)
+}
+
+// Do is DoContext without a deadline
+func (agent Agent) Do(method string, path string, body interface{}) (*etree.Document, error) {
+    return agent.DoContext(context.Background(), method, path, body)
+}
+
+// DoContext sends a request with the given HTTP method to the API endpoint path along with an optional XML body.
+// The body can be an `*etree.Document`, raw XML as `string` or `[]byte`, or any value accepted by `xml.Marshal`.
+// Failed attempts are retried according to the retry policy of the agent.
+//
+// ECG Agent will return the XML document of the response on success, which is nil if the response has no content.
+// On failure, the error is one of `*TransportError`, `*TimeoutError`, `*DecodeError`, `*APIError`, `*AuthError`,
+// `*NotFoundError` or `*RateLimitError`, each carrying the number of attempts made.
+func (agent Agent) DoContext(ctx context.Context, method string, path string, body interface{}) (*etree.Document, error) {
+    payload, err := encodeBody(body)
+    if err != nil {
+        return nil, fmt.Errorf("ecg: invalid request body: %w", err)
+    }
+
+    for attempt := uint(1); ; attempt++ {
+        doc, outcome, err := agent.attempt(ctx, method, path, payload)
+
+        if err == nil {
+            return doc, nil
+        }
+
+        if reqErr, ok := err.(interface{ setAttempts(uint) }); ok {
+            reqErr.setAttempts(attempt)
+        }
+
+        if agent.Retry == nil || attempt >= agent.Retry.MaxAttempts || ctx.Err() != nil || !agent.Retry.retries(method, outcome) {
+            return nil, err
+        }
+
+        delay, ok := agent.Retry.delay(attempt, outcome)
+        if !ok {
+            return nil, err
+        }
+
+        if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Until(deadline) < delay {
+            return nil, err // the next attempt would not complete in time
+        }
+
+        timer := time.NewTimer(delay)
+
+        select {
+        case <-ctx.Done():
+            timer.Stop()
+            return nil, err
+        case <-timer.C:
+        }
+    }
+}
+
+// attempt sends a single request to the API endpoint
+func (agent Agent) attempt(ctx context.Context, method string, path string, payload []byte) (*etree.Document, attemptOutcome, error) {
+    var outcome attemptOutcome
+
+    info := RequestInfo{Method: method, URL: agent.Endpoint + path}
+
+    req, err := agent.newRequest(ctx, method, path, payload)
+    if err != nil {
+        return nil, outcome, fmt.Errorf("ecg: invalid request: %w", err)
+    }
+
+    // waiting comes first so that signatures are not stale by the time the request is sent
+    if agent.RateLimiter != nil {
+        release, err := agent.RateLimiter.Wait(ctx, method, path)
+        if err != nil {
+            return nil, outcome, &TimeoutError{RequestInfo: info, Err: err}
+        }
+
+        defer release()
     }
 
-    resp, body, errs := http.Get(agent.Endpoint + url).End()
+    if agent.hasECGAuthentication() {
+        headers, err := (*agent.ECGAuthentication).headers(req.Method, req.URL.String(), path)
+        if err != nil {
+            return nil, outcome, &AuthError{APIError: APIError{RequestInfo: info, Message: err.Error()}, Err: err}
+        }
+
+        for header, value := range headers {
+            req.Header.Set(header, value)
+        }
+    }
+
+    resp, err := agent.httpClient().Do(req)
+    if err != nil {
+        if ctxErr := ctx.Err(); ctxErr != nil {
+            return nil, outcome, &TimeoutError{RequestInfo: info, Err: ctxErr}
+        }
+
+        outcome.transportErr = true
+        return nil, outcome, &TransportError{RequestInfo: info, Err: err}
+    }
+
+    defer resp.Body.Close()
+
+    statusCode := uint(resp.StatusCode)
+    isSuccess := statusCode >= 200 && statusCode < 300
 
-    var statusCode uint = 503 // error by default
-    errMsg := "Service temporarily unavailable"
+    outcome.statusCode = statusCode
+    outcome.retryAfter = parseRetryAfter(resp.Header)
+
+    content, err := ioutil.ReadAll(resp.Body)
+    if err != nil {
+        if ctxErr := ctx.Err(); ctxErr != nil {
+            return nil, outcome, &TimeoutError{RequestInfo: info, Err: ctxErr}
+        }
+
+        outcome.transportErr = true // connection dropped while reading the response
+        return nil, outcome, &TransportError{RequestInfo: info, Err: err}
+    }
+
+    if len(content) == 0 {
+        if !isSuccess {
+            return nil, outcome, newAPIError(info, statusCode, nil, content, outcome.retryAfter)
+        }
+
+        if method != "GET" { // e.g. 204 No Content after a deletion
+            return nil, outcome, nil
+        }
+
+        return nil, outcome, &DecodeError{RequestInfo: info, StatusCode: statusCode, Body: content, Err: fmt.Errorf("empty response")}
+    }
 
-    if errs == nil && len(errs) < 1 && resp != nil && body != "" {
-        xml, err := u.ParseXML(body)
-        statusCode = uint(resp.StatusCode)
+    doc, err := u.ParseXML(string(content))
+    if err != nil { // failed to parse response
+        return nil, outcome, &DecodeError{RequestInfo: info, StatusCode: statusCode, Body: content, Err: err}
+    }
 
-        if err == nil { // XML is valid
-            if root := xml.Root(); statusCode != 200 || root == nil || root.Tag == "api-base-error" || root.Tag == "html" {
-                extractedMsg, _ := u.ExtractText(root, "//message")
-                errMsg          := u.ReplaceStringWithNil(&extractedMsg, "")
+    if root := doc.Root(); !isSuccess || root == nil || root.Tag == "api-base-error" || root.Tag == "html" {
+        var details *parsers.ErrorDocument
 
-                if errMsg == nil || *errMsg == "" { // replace with default error
-                    errMsgFallback := "Unknown error"
-                    errMsg = &errMsgFallback
-                }
+        if parser, err := agent.parser(); err == nil {
+            details = parser.ParseError(doc)
+        } else {
+            details = parsers.ParseErrorDocument(doc)
+        }
 
-                return nil, &EndpointErrorResponse{
-                    StatusCode: &statusCode,
-                    Message:    errMsg,
-                }
-            }
+        if details == nil { // not an ECG error document, but may still carry a message
+            errMsg, _ := u.ExtractText(root, "//message")
+            details = &parsers.ErrorDocument{Errors: []parsers.ErrorDetail{{Message: strings.TrimSpace(errMsg)}}}
+        }
 
-            return xml, nil
+        if isSuccess { // unexpected error document despite successful status
+            statusCode = 500
         }
 
-        errMsg := "Internal server error"
+        return nil, outcome, newAPIError(info, statusCode, details, content, outcome.retryAfter)
+    }
+
+    return doc, outcome, nil
+}
+
+// newRequest creates a HTTP request to the API endpoint with authorization and content negotiation headers
+func (agent Agent) newRequest(ctx context.Context, method string, path string, payload []byte) (*http.Request, error) {
+    var reader io.Reader
+    if payload != nil {
+        reader = bytes.NewReader(payload)
+    }
+
+    req, err := http.NewRequest(method, agent.Endpoint + path, reader)
+    if err != nil {
+        return nil, err
+    }
+
+    req = req.WithContext(ctx)
+    req.Header.Set("Accept", "application/xml")
+
+    if payload != nil {
+        req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+    }
+
+    if agent.hasECGAuthorization() {
+        req.SetBasicAuth((*agent.ECGAuthorization).Username, (*agent.ECGAuthorization).Password)
+    }
+
+    return req, nil
+}
 
-        return nil, &EndpointErrorResponse{ // failed to parse response
-            StatusCode: &statusCode,
-            Message:    &errMsg,
+// encodeBody encodes a request body to XML
+func encodeBody(body interface{}) ([]byte, error) {
+    switch b := body.(type) {
+    case nil:
+        return nil, nil
+    case *etree.Document:
+        if b == nil {
+            return nil, nil
         }
+
+        return b.WriteToBytes()
+    case []byte:
+        return b, nil
+    case string:
+        return []byte(b), nil
     }
 
-    return nil, &EndpointErrorResponse{
-        StatusCode: &statusCode,
-        Message:    &errMsg,
+    payload, err := xml.Marshal(body)
+    if err != nil {
+        return nil, err
     }
+
+    return append([]byte(xml.Header), payload...), nil
 }
@@ -1,10 +1,13 @@
 package ecg_test
 
 import (
+    "context"
     "encoding/json"
+    "errors"
     "fmt"
     "github.com/GreenVine/ebay-classifieds-api"
     "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    "time"
 )
 
 var agent ecg.Agent
@@ -24,17 +27,22 @@ func ExampleAgent() {
     }
 }
 
-func ExampleAgent_RequestEndpoint() {
-    advertisement, err := agent.RequestEndpoint("/ads/123456", 2000)
-    category, err := agent.RequestEndpoint("/ads", 10000)
+func ExampleAgent_RequestEndpointContext() {
+    ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Second)
+    defer cancel()
 
-    if err != nil { // erroneous HTTP response
-        fmt.Println(*err.StatusCode, *err.Message)
+    advertisement, err := agent.RequestEndpointContext(ctx, "/ads/123456")
+    category, err := agent.RequestEndpointContext(ctx, "/ads")
+
+    if errors.Is(err, ecg.ErrNotFound) { // advertisement or category does not exist
+        fmt.Println("not found")
+    } else if err != nil { // erroneous HTTP response
+        fmt.Println(err)
     } else { // successful response
-        advert, errs, isFatal := auparser.ParseAdvert(advertisement) // parse an Advertisement
-        cat, errs, isFatal := auparser.ParseCategory(category) // parse Advertisements in a category
+        advert, report := auparser.ParseAdvert(advertisement) // parse an Advertisement
+        cat, report := auparser.ParseCategory(category) // parse Advertisements in a category
 
-        if errs != nil && !isFatal {
+        if !report.IsFatal() {
             fmt.Println(advert.Title) // get advertisement title
             fmt.Println(cat.Pagination.CurrentPage) // get current page number
 
@@ -43,3 +51,16 @@ func ExampleAgent_RequestEndpoint() {
         }
     }
 }
+
+func ExampleAgent_GetAdvert() {
+    ctx, cancel := context.WithTimeout(context.Background(), 10 * time.Second)
+    defer cancel()
+
+    advert, report, err := agent.GetAdvert(ctx, 123456)
+
+    if err != nil { // erroneous HTTP response or unparsable advertisement
+        fmt.Println(err)
+    } else {
+        fmt.Println(advert.Title, report.Len())
+    }
+}
@@ -0,0 +1,44 @@
+package ecg
+
+import (
+    "context"
+    "fmt"
+    "github.com/beevik/etree"
+)
+
+// RequestAdvert requests a single advertisement by its ID
+func (agent Agent) RequestAdvert(ctx context.Context, id uint) (*etree.Document, error) {
+    return agent.RequestEndpointContext(ctx, fmt.Sprintf("/ads/%d", id))
+}
+
+// RequestAdverts requests a list of advertisements, optionally filtered by an encoded query string (without `?`)
+func (agent Agent) RequestAdverts(ctx context.Context, query string) (*etree.Document, error) {
+    if query == "" {
+        return agent.RequestEndpointContext(ctx, "/ads")
+    }
+
+    return agent.RequestEndpointContext(ctx, "/ads?" + query)
+}
+
+// RequestCategories requests the category tree
+func (agent Agent) RequestCategories(ctx context.Context) (*etree.Document, error) {
+    return agent.RequestEndpointContext(ctx, "/categories")
+}
+
+// RequestLocations requests the location hierarchy
+func (agent Agent) RequestLocations(ctx context.Context) (*etree.Document, error) {
+    return agent.RequestEndpointContext(ctx, "/locations")
+}
+
+// RequestAttributeMetadata requests the attributes supported by a category
+func (agent Agent) RequestAttributeMetadata(ctx context.Context, categoryID uint) (*etree.Document, error) {
+    return agent.RequestEndpointContext(ctx, fmt.Sprintf("/categories/%d/attributes", categoryID))
+}
+
+// createAdvertPath is the endpoint new advertisements are posted to
+const createAdvertPath = "/ads"
+
+// RequestCreateAdvert posts a new advertisement encoded as an `ad:ad` document on behalf of the authenticated user
+func (agent Agent) RequestCreateAdvert(ctx context.Context, advert *etree.Document) (*etree.Document, error) {
+    return agent.DoContext(ctx, "POST", createAdvertPath, advert)
+}
@@ -0,0 +1,295 @@
+package ecg
+
+import (
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "net/http"
+    "strings"
+    "time"
+)
+
+// Errors classifying a failed request, to be matched with `errors.Is`
+var (
+    ErrTransport    = errors.New("ecg: transport failure")
+    ErrTimeout      = errors.New("ecg: request timed out or cancelled")
+    ErrDecode       = errors.New("ecg: malformed response")
+    ErrAPI          = errors.New("ecg: API error")
+    ErrUnauthorized = errors.New("ecg: authentication failed")
+    ErrNotFound     = errors.New("ecg: not found")
+    ErrRateLimited  = errors.New("ecg: rate limited")
+    ErrParse        = errors.New("ecg: unable to parse response")
+    ErrInvalidDraft = errors.New("ecg: invalid advert draft")
+)
+
+// Errors of a misconfigured marketplace
+var (
+    ErrUnknownMarketplace = errors.New("ecg: no parser registered for marketplace")
+    ErrModelMismatch      = errors.New("ecg: marketplace parser returned another model, use the typed API of the marketplace or the Fetch methods instead")
+    ErrNotSupported       = errors.New("ecg: endpoint not supported by the marketplace parser")
+)
+
+// ErrNoUserCredential is returned when posting through an agent without the user authentication credential,
+// or whose ScopeResolver does not send it when posting
+var ErrNoUserCredential = errors.New("ecg: posting requires the user authentication credential")
+
+// RequestInfo identifies the request that failed and is embedded in every request error
+type RequestInfo struct {
+    Method   string // HTTP method
+    URL      string // Full request URL
+    Attempts uint   // Number of attempts made before giving up
+}
+
+func (info *RequestInfo) setAttempts(attempts uint) {
+    info.Attempts = attempts
+}
+
+func (info RequestInfo) String() string {
+    if info.Attempts > 1 {
+        return fmt.Sprintf("%s %s (%d attempts)", info.Method, info.URL, info.Attempts)
+    }
+
+    return fmt.Sprintf("%s %s", info.Method, info.URL)
+}
+
+// TransportError is a request that failed without a complete response, e.g. connection refused or reset
+type TransportError struct {
+    RequestInfo
+    Err error // Underlying network error
+}
+
+func (e *TransportError) Error() string {
+    return fmt.Sprintf("ecg: %s: %v", e.RequestInfo, e.Err)
+}
+
+// Unwrap returns the underlying network error
+func (e *TransportError) Unwrap() error {
+    return e.Err
+}
+
+// Is matches `ErrTransport`
+func (e *TransportError) Is(target error) bool {
+    return target == ErrTransport
+}
+
+// TimeoutError is a request aborted because its context was cancelled or its deadline exceeded
+type TimeoutError struct {
+    RequestInfo
+    Err error // Context error, either `context.Canceled` or `context.DeadlineExceeded`
+}
+
+func (e *TimeoutError) Error() string {
+    return fmt.Sprintf("ecg: %s: %v", e.RequestInfo, e.Err)
+}
+
+// Unwrap returns the context error
+func (e *TimeoutError) Unwrap() error {
+    return e.Err
+}
+
+// Is matches `ErrTimeout`
+func (e *TimeoutError) Is(target error) bool {
+    return target == ErrTimeout
+}
+
+// DecodeError is a response that could not be parsed as XML
+type DecodeError struct {
+    RequestInfo
+    StatusCode uint   // HTTP Status Code
+    Body       []byte // Raw response body
+    Err        error  // Parsing error
+}
+
+func (e *DecodeError) Error() string {
+    return fmt.Sprintf("ecg: %s: malformed response with status %d: %v", e.RequestInfo, e.StatusCode, e.Err)
+}
+
+// Unwrap returns the parsing error
+func (e *DecodeError) Unwrap() error {
+    return e.Err
+}
+
+// Is matches `ErrDecode`
+func (e *DecodeError) Is(target error) bool {
+    return target == ErrDecode
+}
+
+// APIError is an erroneous response returned by the API
+type APIError struct {
+    RequestInfo
+    StatusCode uint                   // HTTP Status Code
+    Code       string                 // ECG error code (optional)
+    Message    string                 // Status Message
+    Details    *parsers.ErrorDocument // Parsed `api-base-error` document including field errors (optional)
+    Body       []byte                 // Raw response body
+}
+
+func (e *APIError) Error() string {
+    if e.Details != nil && len(e.Details.FieldErrors) > 0 {
+        return fmt.Sprintf("ecg: %s: %d %s", e.RequestInfo, e.StatusCode, e.Details)
+    }
+
+    return fmt.Sprintf("ecg: %s: %d %s", e.RequestInfo, e.StatusCode, e.Message)
+}
+
+// FieldErrors returns the field-level validation errors reported by the API
+func (e *APIError) FieldErrors() []parsers.FieldError {
+    if e.Details == nil {
+        return nil
+    }
+
+    return e.Details.FieldErrors
+}
+
+// Is matches `ErrAPI`
+func (e *APIError) Is(target error) bool {
+    return target == ErrAPI
+}
+
+// AuthError is a request rejected for missing or invalid credentials, or whose credentials could not be signed
+type AuthError struct {
+    APIError
+    Err error // Signing error, if the request was never sent
+}
+
+// Unwrap returns the signing error, or the API error otherwise
+func (e *AuthError) Unwrap() error {
+    if e.Err != nil {
+        return e.Err
+    }
+
+    return &e.APIError
+}
+
+func (e *AuthError) Error() string {
+    if e.Err != nil {
+        return fmt.Sprintf("ecg: %s: %v", e.RequestInfo, e.Err)
+    }
+
+    return e.APIError.Error()
+}
+
+// Is matches `ErrUnauthorized`
+func (e *AuthError) Is(target error) bool {
+    return target == ErrUnauthorized
+}
+
+// NotFoundError is a request for a resource that does not exist
+type NotFoundError struct {
+    APIError
+}
+
+// Unwrap returns the API error
+func (e *NotFoundError) Unwrap() error {
+    return &e.APIError
+}
+
+// Is matches `ErrNotFound`
+func (e *NotFoundError) Is(target error) bool {
+    return target == ErrNotFound
+}
+
+// RateLimitError is a request rejected for exceeding the request quota
+type RateLimitError struct {
+    APIError
+    RetryAfter time.Duration // Delay requested by the API before trying again, zero if unknown
+}
+
+// Unwrap returns the API error
+func (e *RateLimitError) Unwrap() error {
+    return &e.APIError
+}
+
+// Is matches `ErrRateLimited`
+func (e *RateLimitError) Is(target error) bool {
+    return target == ErrRateLimited
+}
+
+// ParseFailureError is a well-formed response that could not be parsed into a model
+type ParseFailureError struct {
+    Report *parsers.ParseReport // Errors reported by the parser
+}
+
+func (e *ParseFailureError) Error() string {
+    if e.Report.Len() < 1 {
+        return ErrParse.Error()
+    }
+
+    return fmt.Sprintf("%s: %v", ErrParse, e.Report)
+}
+
+// Unwrap returns the first fatal parsing error
+func (e *ParseFailureError) Unwrap() error {
+    return e.Report.Err()
+}
+
+// Is matches `ErrParse`
+func (e *ParseFailureError) Is(target error) bool {
+    return target == ErrParse
+}
+
+// ValidationError is an advert draft rejected before it was sent, listing every offending field
+// in the form of the field errors returned by the API, e.g. `title` or `attributes.cars.carmake_s`
+type ValidationError struct {
+    Fields []parsers.FieldError // Offending fields
+    Err    error                // `*aumodels.ValidationError` if attributes do not conform to the schema of the category
+}
+
+func (e *ValidationError) Error() string {
+    fields := make([]string, len(e.Fields))
+    for i, field := range e.Fields {
+        fields[i] = field.String()
+    }
+
+    return fmt.Sprintf("%s: %s", ErrInvalidDraft, strings.Join(fields, "; "))
+}
+
+// Unwrap returns the attribute validation error, if any
+func (e *ValidationError) Unwrap() error {
+    return e.Err
+}
+
+// FieldErrors returns the offending fields, like `APIError.FieldErrors` does for drafts rejected by the API
+func (e *ValidationError) FieldErrors() []parsers.FieldError {
+    return e.Fields
+}
+
+// Is matches `ErrInvalidDraft`
+func (e *ValidationError) Is(target error) bool {
+    return target == ErrInvalidDraft
+}
+
+// newAPIError creates the most specific error type for an erroneous API response
+func newAPIError(info RequestInfo, statusCode uint, details *parsers.ErrorDocument, body []byte, retryAfter time.Duration) error {
+    var code, message string
+
+    if details != nil {
+        code, message = details.Code(), details.Message()
+    }
+
+    if message == "" {
+        if message = http.StatusText(int(statusCode)); message == "" {
+            message = "Unknown error"
+        }
+    }
+
+    apiErr := APIError{
+        RequestInfo: info,
+        StatusCode:  statusCode,
+        Code:        code,
+        Message:     message,
+        Details:     details,
+        Body:        body,
+    }
+
+    switch statusCode {
+    case http.StatusUnauthorized, http.StatusForbidden:
+        return &AuthError{APIError: apiErr}
+    case http.StatusNotFound:
+        return &NotFoundError{APIError: apiErr}
+    case http.StatusTooManyRequests:
+        return &RateLimitError{APIError: apiErr, RetryAfter: retryAfter}
+    }
+
+    return &apiErr
+}
@@ -1,18 +1,12 @@
 module github.com/GreenVine/ebay-classifieds-api
 
-go 1.12
+go 1.13
 
 require (
 	github.com/beevik/etree v1.1.0
-	github.com/elazarl/goproxy v0.0.0-20190410145444-c548f45dcf1d // indirect
-	github.com/elazarl/goproxy/ext v0.0.0-20190410145444-c548f45dcf1d // indirect
 	github.com/jaytaylor/html2text v0.0.0-20190311042500-a93a6c6ea053
 	github.com/mattn/go-runewidth v0.0.4 // indirect
-	github.com/moul/http2curl v1.0.0 // indirect
 	github.com/olekukonko/tablewriter v0.0.1 // indirect
-	github.com/parnurzeal/gorequest v0.2.15
-	github.com/pkg/errors v0.8.1 // indirect
-	github.com/smartystreets/goconvey v0.0.0-20190330032615-68dc04aab96a // indirect
 	github.com/ssor/bom v0.0.0-20170718123548-6386211fdfcf // indirect
-	golang.org/x/net v0.0.0-20190318221613-d196dffd7c2b // indirect
+	golang.org/x/net v0.0.0-20190318221613-d196dffd7c2b
 )
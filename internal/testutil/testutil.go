@@ -0,0 +1,52 @@
+// Package testutil holds the helpers shared by the tests of the marketplace parsers
+package testutil
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/beevik/etree"
+    "path"
+    "testing"
+)
+
+// ReadFixture reads an XML fixture relative to the directory of the package under test, failing the test if it
+// cannot be read, e.g. `ReadFixture(t, "testdata/ads.xml")`
+func ReadFixture(t testing.TB, file string) *etree.Document {
+    t.Helper()
+
+    doc := etree.NewDocument()
+
+    if err := doc.ReadFromFile(file); err != nil {
+        t.Fatalf("unable to read fixture %s: %v", file, err)
+    }
+
+    return doc
+}
+
+// RejectUnexpectedResponses checks that every method of a parser rejects an empty response and a response
+// of another kind, using the `advert.xml` and `categories.xml` fixtures of a directory
+func RejectUnexpectedResponses(t *testing.T, parser parsers.Parser, dir string) {
+    t.Helper()
+
+    advert := ReadFixture(t, path.Join(dir, "advert.xml"))
+    categories := ReadFixture(t, path.Join(dir, "categories.xml"))
+
+    checks := []struct {
+        method string
+        parse  func(*etree.Document) (interface{}, *parsers.ParseReport)
+        other  *etree.Document
+    }{
+        {"ParseAdvert", parser.ParseAdvert, categories},
+        {"ParseAdvertList", parser.ParseAdvertList, advert},
+        {"ParseCategories", parser.ParseCategories, advert},
+    }
+
+    for _, check := range checks {
+        if model, report := check.parse(check.other); model != nil || !report.IsFatal() {
+            t.Errorf("expected %s to reject a response of another kind, got %v", check.method, report)
+        }
+
+        if model, report := check.parse(nil); model != nil || !report.IsFatal() {
+            t.Errorf("expected %s to reject an empty response, got %v", check.method, report)
+        }
+    }
+}
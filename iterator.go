@@ -0,0 +1,240 @@
+package ecg
+
+import (
+    "context"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    aumodels "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+)
+
+// AdvertCursor iterates over the advertisements of a search across all its pages.
+//
+//     cursor := agent.SearchAll(ctx, query)
+//     defer cursor.Close()
+//
+//     for cursor.Next() {
+//         fmt.Println(cursor.Advert().Title)
+//     }
+//
+//     if err := cursor.Err(); err != nil { ... }
+//
+// A cursor is not safe for concurrent use.
+type AdvertCursor struct {
+    Prefetch bool // fetch the next page in the background while the current one is consumed, set before the first Next
+
+    agent  Agent
+    ctx    context.Context
+    cancel context.CancelFunc
+    query  SearchQuery
+
+    adverts    []aumodels.Advert
+    index      int
+    pagination *aumodels.CategoryPagination
+    seen       map[uint]struct{}
+    listed     map[string]struct{} // IDs of all `ad:ad` elements fetched so far, including those the parser dropped
+    report     parsers.ParseReport
+    err        error
+
+    nextPage uint
+    hasNext  bool
+    closed   bool
+    pending  chan searchPage
+}
+
+// searchPage is the outcome of fetching a single page
+type searchPage struct {
+    category *aumodels.Category
+    ids      []string // IDs of the `ad:ad` elements in the response, including those the parser dropped
+    report   *parsers.ParseReport
+    err      error
+}
+
+// SearchAll returns a cursor over all advertisements matching the query, starting from the page of the query.
+// Pages are fetched on demand until the number of matched entries is exhausted or the context ends,
+// and advertisements that shift between pages while iterating are only returned once.
+func (agent Agent) SearchAll(ctx context.Context, query SearchQuery) *AdvertCursor {
+    ctx, cancel := context.WithCancel(ctx)
+
+    return &AdvertCursor{
+        agent:    agent,
+        ctx:      ctx,
+        cancel:   cancel,
+        query:    query,
+        index:    -1,
+        seen:     make(map[uint]struct{}),
+        listed:   make(map[string]struct{}),
+        nextPage: query.Page,
+        hasNext:  true,
+    }
+}
+
+// Next advances the cursor to the next advertisement, fetching the next page if required.
+// It returns false once all advertisements were returned or an error occurred.
+func (c *AdvertCursor) Next() bool {
+    if err := c.ctx.Err(); err != nil && c.err == nil && !c.closed {
+        c.err = err
+    }
+
+    for c.err == nil && !c.closed {
+        for c.index+1 < len(c.adverts) {
+            c.index++
+
+            id := c.adverts[c.index].ID
+            if _, duplicate := c.seen[id]; duplicate {
+                continue
+            }
+
+            c.seen[id] = struct{}{}
+            return true
+        }
+
+        if !c.hasNext {
+            return false
+        }
+
+        c.load()
+    }
+
+    return false
+}
+
+// Advert returns the current advertisement
+func (c *AdvertCursor) Advert() *aumodels.Advert {
+    if c.index < 0 || c.index >= len(c.adverts) {
+        return nil
+    }
+
+    return &c.adverts[c.index]
+}
+
+// Pagination returns the pagination of the most recently fetched page
+func (c *AdvertCursor) Pagination() *aumodels.CategoryPagination {
+    return c.pagination
+}
+
+// Report returns the non-fatal parsing errors of all pages fetched so far
+func (c *AdvertCursor) Report() *parsers.ParseReport {
+    return &c.report
+}
+
+// Err returns the error that stopped the iteration, if any
+func (c *AdvertCursor) Err() error {
+    return c.err
+}
+
+// Close stops the iteration and aborts any page being prefetched
+func (c *AdvertCursor) Close() {
+    c.closed = true
+    c.cancel()
+}
+
+// load replaces the current page with the next one
+func (c *AdvertCursor) load() {
+    var result searchPage
+
+    if c.pending != nil {
+        select {
+        case result = <-c.pending:
+        case <-c.ctx.Done():
+            result.err = c.ctx.Err()
+        }
+
+        c.pending = nil
+    } else {
+        result = c.fetch(c.nextPage)
+    }
+
+    if result.err != nil {
+        c.err = result.err
+        c.hasNext = false
+        return
+    }
+
+    page := c.nextPage
+    c.adverts, c.index = result.category.Adverts, -1
+    c.pagination = result.category.Pagination
+    c.report.Merge(result.report)
+    c.nextPage = page + 1
+    c.hasNext = c.hasMorePages(page, len(result.ids), c.list(result.ids))
+
+    if c.hasNext && c.Prefetch {
+        c.pending = make(chan searchPage, 1)
+
+        go func(pending chan<- searchPage, page uint) {
+            pending <- c.fetch(page)
+        }(c.pending, c.nextPage)
+    }
+}
+
+// list records the advertisement IDs of a page, returning how many of them were not listed before
+func (c *AdvertCursor) list(ids []string) int {
+    unlisted := 0
+
+    for _, id := range ids {
+        if _, listed := c.listed[id]; !listed && id != "" {
+            c.listed[id] = struct{}{}
+            unlisted++
+        }
+    }
+
+    return unlisted
+}
+
+// hasMorePages reports whether another page follows the given page with the given number of advertisements,
+// of which `unlisted` were not on previous pages. Without pagination or a known number of entries, pages are
+// fetched until one adds no advertisements, as the API may repeat the last page for pages out of range.
+func (c *AdvertCursor) hasMorePages(page uint, count int, unlisted int) bool {
+    if count < 1 {
+        return false
+    }
+
+    if c.pagination == nil || c.pagination.EntrySize == 0 {
+        return unlisted > 0
+    }
+
+    size := c.pagination.PageSize // the API may cap the page size below the requested one
+    if size == 0 {
+        size = c.query.Size
+    }
+
+    if size == 0 {
+        return true
+    }
+
+    return (page + 1) * size < c.pagination.EntrySize
+}
+
+// fetch requests and parses a single page, and must not modify the cursor as it may run concurrently
+func (c *AdvertCursor) fetch(page uint) searchPage {
+    if err := c.ctx.Err(); err != nil {
+        return searchPage{err: err}
+    }
+
+    doc, err := c.agent.RequestSearch(c.ctx, c.query.WithPage(page))
+    if err != nil {
+        return searchPage{err: err}
+    }
+
+    var category *aumodels.Category
+
+    report, err := typed(c.agent.parse(doc, parsers.Parser.ParseAdvertList))(&category)
+    if err != nil {
+        return searchPage{err: err}
+    }
+
+    return searchPage{category: category, ids: advertIDs(doc), report: report}
+}
+
+// advertIDs returns the raw IDs of the advertisement elements in a search response, empty for elements without one
+func advertIDs(doc *etree.Document) []string {
+    if doc == nil || doc.Root() == nil {
+        return nil
+    }
+
+    var ids []string
+    for _, ad := range doc.Root().SelectElements("ad:ad") {
+        ids = append(ids, ad.SelectAttrValue("id", ""))
+    }
+
+    return ids
+}
@@ -0,0 +1,75 @@
+package ecg
+
+import (
+    "context"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    caparser "github.com/GreenVine/ebay-classifieds-api/parsers/ca"
+    camodels "github.com/GreenVine/ebay-classifieds-api/parsers/ca/models"
+    deparser "github.com/GreenVine/ebay-classifieds-api/parsers/de"
+    demodels "github.com/GreenVine/ebay-classifieds-api/parsers/de/models"
+)
+
+// The built-in parsers of Australia, Germany and Canada are registered by importing this package,
+// as the typed APIs below import their packages.
+
+// GermanAgent is the typed API of the German marketplace, returning the models of `parsers/de`
+type GermanAgent struct {
+    agent Agent
+}
+
+// German returns the typed API of the German marketplace with the settings of the agent,
+// whose `Marketplace` is replaced with `deparser.Marketplace`
+func (agent Agent) German() GermanAgent {
+    agent.Marketplace = deparser.Marketplace
+    return GermanAgent{agent: agent}
+}
+
+// GetAdvert requests and parses a single advertisement using the German models
+func (german GermanAgent) GetAdvert(ctx context.Context, id uint) (advert *demodels.Advert, report *parsers.ParseReport, err error) {
+    report, err = typed(german.agent.FetchAdvert(ctx, id))(&advert)
+    return advert, report, err
+}
+
+// SearchAdverts requests and parses a single page of advertisements matching the query using the German models
+func (german GermanAgent) SearchAdverts(ctx context.Context, query SearchQuery) (category *demodels.Category, report *parsers.ParseReport, err error) {
+    report, err = typed(german.agent.FetchAdvertList(ctx, query))(&category)
+    return category, report, err
+}
+
+// GetCategoryTree requests and parses the categories using the German models,
+// returning every top-level category in document order as the root of its own tree.
+func (german GermanAgent) GetCategoryTree(ctx context.Context) (categories []demodels.Categories, report *parsers.ParseReport, err error) {
+    report, err = typed(german.agent.FetchCategories(ctx))(&categories)
+    return categories, report, err
+}
+
+// CanadianAgent is the typed API of the Canadian marketplace, returning the models of `parsers/ca`
+type CanadianAgent struct {
+    agent Agent
+}
+
+// Canadian returns the typed API of the Canadian marketplace with the settings of the agent,
+// whose `Marketplace` is replaced with `caparser.Marketplace`
+func (agent Agent) Canadian() CanadianAgent {
+    agent.Marketplace = caparser.Marketplace
+    return CanadianAgent{agent: agent}
+}
+
+// GetAdvert requests and parses a single advertisement using the Canadian models
+func (canadian CanadianAgent) GetAdvert(ctx context.Context, id uint) (advert *camodels.Advert, report *parsers.ParseReport, err error) {
+    report, err = typed(canadian.agent.FetchAdvert(ctx, id))(&advert)
+    return advert, report, err
+}
+
+// SearchAdverts requests and parses a single page of advertisements matching the query using the Canadian models
+func (canadian CanadianAgent) SearchAdverts(ctx context.Context, query SearchQuery) (category *camodels.Category, report *parsers.ParseReport, err error) {
+    report, err = typed(canadian.agent.FetchAdvertList(ctx, query))(&category)
+    return category, report, err
+}
+
+// GetCategoryTree requests and parses the categories using the Canadian models,
+// returning every top-level category in document order as the root of its own tree.
+func (canadian CanadianAgent) GetCategoryTree(ctx context.Context) (categories []camodels.Categories, report *parsers.ParseReport, err error) {
+    report, err = typed(canadian.agent.FetchCategories(ctx))(&categories)
+    return categories, report, err
+}
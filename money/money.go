@@ -0,0 +1,244 @@
+// Package money represents prices exactly, as integer amounts of the minor unit of their currency.
+package money
+
+import (
+    "encoding/json"
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+)
+
+// Errors of amounts that cannot be parsed
+var (
+    ErrSyntax    = errors.New("invalid decimal amount")
+    ErrPrecision = errors.New("more decimal places than the minor unit of the currency")
+    ErrRange     = errors.New("amount out of range")
+)
+
+// minorUnits are the decimal places of currencies whose minor unit is not a hundredth (ISO 4217)
+var minorUnits = map[string]int{
+    "BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+    "BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+    "PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorUnits returns the number of decimal places of a currency, two for unknown currencies
+func MinorUnits(currency string) int {
+    if units, ok := minorUnits[strings.ToUpper(currency)]; ok {
+        return units
+    }
+
+    return 2
+}
+
+// Amount is an exact monetary amount
+type Amount struct {
+    Minor    int64  // Amount in minor units of the currency, e.g. cents
+    Currency string // ISO 4217 currency code, empty if unknown
+}
+
+// New creates an amount from minor units
+func New(minor int64, currency string) Amount {
+    return Amount{Minor: minor, Currency: strings.ToUpper(currency)}
+}
+
+// ParseError is a decimal text that could not be parsed into an amount
+type ParseError struct {
+    Text string // Text that was parsed
+    Err  error  // `ErrSyntax`, `ErrPrecision` or `ErrRange`
+}
+
+func (e *ParseError) Error() string {
+    return fmt.Sprintf("money: parsing %q: %v", e.Text, e.Err)
+}
+
+// Unwrap returns the reason of the failure
+func (e *ParseError) Unwrap() error {
+    return e.Err
+}
+
+// Parse reads a decimal text such as `19.99` or `-5` in a currency without rounding.
+// Trailing zeros beyond the minor unit are accepted, other excess decimal places are an error.
+func Parse(text string, currency string) (Amount, error) {
+    fail := func(err error) (Amount, error) {
+        return Amount{}, &ParseError{Text: text, Err: err}
+    }
+
+    s := strings.TrimSpace(text)
+    negative := false
+
+    if s != "" && (s[0] == '-' || s[0] == '+') {
+        negative, s = s[0] == '-', s[1:]
+    }
+
+    whole, fraction := s, ""
+    if i := strings.IndexByte(s, '.'); i >= 0 {
+        whole, fraction = s[:i], s[i+1:]
+    }
+
+    if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+        return fail(ErrSyntax)
+    }
+
+    units := MinorUnits(currency)
+
+    if len(fraction) > units {
+        if strings.Trim(fraction[units:], "0") != "" {
+            return fail(ErrPrecision)
+        }
+
+        fraction = fraction[:units]
+    }
+
+    digits := strings.TrimLeft(whole + fraction + strings.Repeat("0", units - len(fraction)), "0")
+    if digits == "" {
+        digits = "0"
+    }
+
+    minor, err := strconv.ParseInt(digits, 10, 64)
+    if err != nil {
+        return fail(ErrRange)
+    }
+
+    if negative {
+        minor = -minor
+    }
+
+    return New(minor, currency), nil
+}
+
+func isDigits(s string) bool {
+    for _, c := range s {
+        if c < '0' || c > '9' {
+            return false
+        }
+    }
+
+    return true
+}
+
+// IsZero reports whether the amount is zero
+func (a Amount) IsZero() bool {
+    return a.Minor == 0
+}
+
+// Decimal returns the amount as decimal text with the number of decimal places of its currency, e.g. `19.99`
+func (a Amount) Decimal() string {
+    units := MinorUnits(a.Currency)
+
+    var magnitude uint64
+    if a.Minor == math.MinInt64 {
+        magnitude = uint64(math.MaxInt64) + 1
+    } else if a.Minor < 0 {
+        magnitude = uint64(-a.Minor)
+    } else {
+        magnitude = uint64(a.Minor)
+    }
+
+    digits := strconv.FormatUint(magnitude, 10)
+    if len(digits) <= units {
+        digits = strings.Repeat("0", units - len(digits) + 1) + digits
+    }
+
+    sign := ""
+    if a.Minor < 0 {
+        sign = "-"
+    }
+
+    if units == 0 {
+        return sign + digits
+    }
+
+    return sign + digits[:len(digits) - units] + "." + digits[len(digits) - units:]
+}
+
+// String returns the amount followed by its currency code, e.g. `19.99 AUD`
+func (a Amount) String() string {
+    if a.Currency == "" {
+        return a.Decimal()
+    }
+
+    return a.Decimal() + " " + a.Currency
+}
+
+// Format returns the amount for display with a currency symbol, e.g. `$19.99` or `-$5.00`,
+// falling back to the currency code if the symbol is empty
+func (a Amount) Format(symbol string) string {
+    if symbol == "" {
+        return a.String()
+    }
+
+    if decimal := a.Decimal(); strings.HasPrefix(decimal, "-") {
+        return "-" + symbol + decimal[1:]
+    } else {
+        return symbol + decimal
+    }
+}
+
+// amountJSON is the JSON representation of an amount, keeping the decimal text exact
+type amountJSON struct {
+    Value    string `json:"value"`
+    Minor    int64  `json:"minor"`
+    Currency string `json:"currency,omitempty"`
+}
+
+// MarshalJSON encodes the amount as `{"value": "19.99", "minor": 1999, "currency": "AUD"}`
+func (a Amount) MarshalJSON() ([]byte, error) {
+    return json.Marshal(amountJSON{Value: a.Decimal(), Minor: a.Minor, Currency: a.Currency})
+}
+
+// UnmarshalJSON decodes an amount encoded by MarshalJSON, preferring the decimal value over the minor units
+func (a *Amount) UnmarshalJSON(data []byte) error {
+    var decoded amountJSON
+    if err := json.Unmarshal(data, &decoded); err != nil {
+        return err
+    }
+
+    if decoded.Value == "" {
+        *a = New(decoded.Minor, decoded.Currency)
+        return nil
+    }
+
+    amount, err := Parse(decoded.Value, decoded.Currency)
+    if err != nil {
+        return err
+    }
+
+    *a = amount
+    return nil
+}
+
+// MarshalXML encodes the amount as decimal text with the currency as attribute, e.g. `<amount currency="AUD">19.99</amount>`
+func (a Amount) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+    if a.Currency != "" {
+        start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "currency"}, Value: a.Currency})
+    }
+
+    return e.EncodeElement(a.Decimal(), start)
+}
+
+// UnmarshalXML decodes an amount encoded by MarshalXML
+func (a *Amount) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+    var text string
+    if err := d.DecodeElement(&text, &start); err != nil {
+        return err
+    }
+
+    var currency string
+    for _, attr := range start.Attr {
+        if attr.Name.Local == "currency" {
+            currency = attr.Value
+        }
+    }
+
+    amount, err := Parse(text, currency)
+    if err != nil {
+        return err
+    }
+
+    *a = amount
+    return nil
+}
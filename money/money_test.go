@@ -0,0 +1,106 @@
+package money_test
+
+import (
+    "encoding/json"
+    "encoding/xml"
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "math"
+    "testing"
+)
+
+func TestParse(t *testing.T) {
+    tests := []struct {
+        text     string
+        currency string
+        minor    int64
+        err      error
+    }{
+        {"19.99", "AUD", 1999, nil},
+        {"16.49", "EUR", 1649, nil},
+        {"249.0", "EUR", 24900, nil},
+        {"250.000", "AUD", 25000, nil},
+        {" 5 ", "CAD", 500, nil},
+        {".5", "CAD", 50, nil},
+        {"-5.25", "AUD", -525, nil},
+        {"+3", "AUD", 300, nil},
+        {"1500", "JPY", 1500, nil},
+        {"1.234", "KWD", 1234, nil},
+        {"92233720368547758.07", "AUD", math.MaxInt64, nil},
+        {"19.999", "AUD", 0, money.ErrPrecision},
+        {"1.5", "JPY", 0, money.ErrPrecision},
+        {"92233720368547758.08", "AUD", 0, money.ErrRange},
+        {"", "AUD", 0, money.ErrSyntax},
+        {".", "AUD", 0, money.ErrSyntax},
+        {"1e3", "AUD", 0, money.ErrSyntax},
+        {"1,50", "EUR", 0, money.ErrSyntax},
+    }
+
+    for _, test := range tests {
+        amount, err := money.Parse(test.text, test.currency)
+
+        if !errors.Is(err, test.err) || err == nil && test.err != nil {
+            t.Errorf("Parse(%q): expected error %v, got %v", test.text, test.err, err)
+        } else if err == nil && amount.Minor != test.minor {
+            t.Errorf("Parse(%q): expected %d, got %d", test.text, test.minor, amount.Minor)
+        }
+    }
+}
+
+func TestFormat(t *testing.T) {
+    tests := []struct {
+        amount  money.Amount
+        decimal string
+        format  string
+    }{
+        {money.New(1999, "aud"), "19.99", "$19.99"},
+        {money.New(5, "AUD"), "0.05", "$0.05"},
+        {money.New(-525, "AUD"), "-5.25", "-$5.25"},
+        {money.New(1500, "JPY"), "1500", "$1500"},
+        {money.New(1234, "KWD"), "1.234", "$1.234"},
+        {money.New(math.MinInt64, "AUD"), "-92233720368547758.08", "-$92233720368547758.08"},
+    }
+
+    for _, test := range tests {
+        if decimal := test.amount.Decimal(); decimal != test.decimal {
+            t.Errorf("expected %s, got %s", test.decimal, decimal)
+        }
+
+        if format := test.amount.Format("$"); format != test.format {
+            t.Errorf("expected %s, got %s", test.format, format)
+        }
+    }
+
+    if s := money.New(1999, "AUD").Format(""); s != "19.99 AUD" {
+        t.Errorf("expected currency code without symbol, got %s", s)
+    }
+}
+
+func TestMarshal(t *testing.T) {
+    amount := money.New(1999, "AUD")
+
+    data, err := json.Marshal(amount)
+    if err != nil || string(data) != `{"value":"19.99","minor":1999,"currency":"AUD"}` {
+        t.Fatalf("unexpected JSON %s: %v", data, err)
+    }
+
+    var decoded money.Amount
+    if err := json.Unmarshal(data, &decoded); err != nil || decoded != amount {
+        t.Errorf("unexpected JSON round trip %+v: %v", decoded, err)
+    }
+
+    data, err = xml.Marshal(struct {
+        XMLName xml.Name     `xml:"price"`
+        Amount  money.Amount `xml:"amount"`
+    }{Amount: amount})
+    if err != nil || string(data) != `<price><amount currency="AUD">19.99</amount></price>` {
+        t.Fatalf("unexpected XML %s: %v", data, err)
+    }
+
+    var price struct {
+        Amount money.Amount `xml:"amount"`
+    }
+    if err := xml.Unmarshal(data, &price); err != nil || price.Amount != amount {
+        t.Errorf("unexpected XML round trip %+v: %v", price.Amount, err)
+    }
+}
@@ -0,0 +1,303 @@
+package parsers
+
+import (
+    "fmt"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+    "time"
+)
+
+// Pagination is the page control of an `ad:ads` response, converted by marketplace parsers into their models
+type Pagination struct {
+    CurrentPage uint
+    PageSize    uint
+    EntrySize   uint // number of matched entries, zero if unknown
+}
+
+// WalkAdverts is to pass every `ad:ad` element of a raw XML response to build in document order,
+// stopping at the first fatal error. It returns the `ad:ads` root element, or nil if the response was rejected.
+func WalkAdverts(doc *etree.Document, report *ParseReport, build func(advert *etree.Element)) *etree.Element {
+    if doc == nil {
+        report.Reporter(0).Fatal("", ErrEmptyResponse)
+        return nil
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "ad" || root.Tag != "ads" {
+        report.Reporter(0).Fatal("ads", ErrUnexpectedResponse)
+        return nil
+    }
+
+    for _, advert := range root.SelectElements("ad") { // build each advertisement
+        build(advert)
+
+        if report.IsFatal() { // critical error that ends the entire response
+            return nil
+        }
+    }
+
+    return root
+}
+
+// ParsePagination reads the page control of an `ad:ads` root element, replacing missing values with zero
+func ParsePagination(root *etree.Element, reporter Reporter) Pagination {
+    currentPage := u.FallbackUintWithReport(
+        u.ExtractTextAsUint(root, "./ad:ads-search-options/ad:page"))(
+        0, reporter, "category/root/current")
+    pageSize := u.FallbackUintWithReport(
+        u.ExtractTextAsUint(root, "./ad:ads-search-options/ad:size"))(
+        0, reporter, "category/root/size")
+    matchedEntries := u.FallbackUintWithReport(
+        u.ExtractTextAsUint(root, "./types:paging/types:numFound"))(
+        0, reporter, "category/matched_entries")
+
+    return Pagination{
+        CurrentPage: currentPage,
+        PageSize:    pageSize,
+        EntrySize:   matchedEntries,
+    }
+}
+
+// AdvertNode is an advertisement of a response with the fields shared by all marketplaces, from which
+// marketplace parsers build their models. Prices, pictures, attributes and names are left to the marketplace
+// as they differ between marketplaces or may be localised.
+type AdvertNode struct {
+    Element     *etree.Element // element of the advertisement, for marketplace-specific fields
+    Reporter    Reporter       // reporter attributing errors to the advertisement
+    ID          uint
+    Type        string
+    UserID      uint
+    Status      string
+    PosterType  string
+    Title       string
+    Description Description
+    Contact     ContactNode
+    Category    *AdvertCategoryNode // nil if missing or without ID
+    Position    PositionNode
+    Timestamp   TimestampNode
+}
+
+// Description holds the representations of a description chosen by the parsing options, nil if not chosen or empty
+type Description struct {
+    RawHTML  *string
+    Base64   *string
+    Text     *string
+    SafeHTML *string
+}
+
+// ContactNode is the contact of the poster of an advertisement
+type ContactNode struct {
+    Name  string
+    Phone string // without spaces
+}
+
+// AdvertCategoryNode is the category of an advertisement, whose name is left to the marketplace
+type AdvertCategoryNode struct {
+    Element       *etree.Element
+    ID            uint
+    Slug          string
+    ParentSlug    string
+    ChildrenCount uint
+}
+
+// PositionNode is the address and the locations of an advertisement
+type PositionNode struct {
+    Address    string
+    City       string
+    State      string
+    Country    string
+    Coordinate *CoordinateNode // nil if missing or invalid
+    Locations  []LocationNode  // in document order
+}
+
+// CoordinateNode is the geographic coordinate of an address
+type CoordinateNode struct {
+    Longitude float64
+    Latitude  float64
+}
+
+// LocationNode is a location of an advertisement, whose name is left to the marketplace
+type LocationNode struct {
+    Element  *etree.Element
+    ID       uint
+    ParentID uint
+}
+
+// TimestampNode holds the times of an advertisement in UTC, nil if missing or invalid
+type TimestampNode struct {
+    CreationTime     *time.Time
+    ModificationTime *time.Time
+    StartTime        *time.Time
+    EndTime          *time.Time
+}
+
+// ParseAdvertNode is to build the shared fields of an `ad:ad` element, producing the representations of the
+// description chosen by options. An advertisement without a valid ID is skipped, returning nil.
+func ParseAdvertNode(ad *etree.Element, report *ParseReport, options Options) *AdvertNode {
+    advertID, err := u.ConvString2Uint(u.ExtractAttrByTag(ad, "id"))
+    if err != nil {
+        report.Reporter(0).Skip("ads/ad/id", err)
+        return nil
+    }
+
+    reporter := report.Reporter(advertID) // errors of the fields below are attributed to the advertisement
+
+    node := AdvertNode{Element: ad, Reporter: reporter, ID: advertID}
+
+    node.Type, _ = u.ExtractText(ad, "./ad:ad-type/ad:value")
+    node.UserID, _ = u.ConvString2Uint(u.ExtractText(ad, "./ad:user-id"))
+    node.Status, _ = u.ExtractText(ad, "./ad:ad-status/ad:value")
+    node.PosterType, _ = u.ExtractText(ad, "./ad:poster-type/ad:value")
+
+    node.Contact = parseContact(ad)
+    node.Category = parseAdvertCategory(ad, reporter)
+    node.Position = parsePosition(ad, reporter)
+
+    node.Title = u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:title"))(
+        "", reporter, "ads/ad/title")
+
+    node.Description = parseDescription(ad, options, reporter)
+
+    node.Timestamp = TimestampNode{
+        CreationTime:     parseTimestamp(ad.FindElement("./ad:creation-date-time")),
+        ModificationTime: parseTimestamp(ad.FindElement("./ad:modification-date-time")),
+        StartTime:        parseTimestamp(ad.FindElement("./ad:start-date-time")),
+        EndTime:          parseTimestamp(ad.FindElement("./ad:end-date-time")),
+    }
+
+    return &node
+}
+
+func parseDescription(ad *etree.Element, options Options, reporter Reporter) Description {
+    var built Description
+
+    rawHTML := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:description"))(
+        "", reporter, "ads/ad/desc_excerpt_html")
+
+    if rawHTML == "" {
+        return built
+    }
+
+    if options.Produces(DescriptionRawHTML) {
+        built.RawHTML = &rawHTML
+    }
+
+    if options.Produces(DescriptionBase64) {
+        if encoded, err := u.FormatHTML2Base64(rawHTML); err != nil {
+            reporter.Report("ads/ad/desc_excerpt_plain_b64", err)
+        } else {
+            built.Base64 = u.ReplaceStringWithNil(&encoded, "")
+        }
+    }
+
+    if options.Produces(DescriptionText) {
+        if text, err := u.ExtractHTMLText(rawHTML); err != nil {
+            reporter.Report("ads/ad/description", err)
+        } else {
+            built.Text = u.ReplaceStringWithNil(&text, "")
+        }
+    }
+
+    if options.Produces(DescriptionSafeHTML) {
+        if safeHTML, err := u.SanitizeHTML(rawHTML, options.AllowedTags); err != nil {
+            reporter.Report("ads/ad/description_html", err)
+        } else {
+            built.SafeHTML = u.ReplaceStringWithNil(&safeHTML, "")
+        }
+    }
+
+    return built
+}
+
+func parseContact(ad *etree.Element) ContactNode {
+    name, _  := u.ExtractText(ad, "./ad:poster-contact-name")
+    phone, _ := u.ExtractText(ad, "./ad:phone")
+
+    return ContactNode{
+        Name:  name,
+        Phone: strings.Replace(phone, " ", "", -1),
+    }
+}
+
+func parseAdvertCategory(ad *etree.Element, reporter Reporter) *AdvertCategoryNode {
+    cat := ad.FindElement("./cat:category")
+    if cat == nil {
+        return nil
+    }
+
+    catID, err := u.ConvString2Uint(u.ExtractAttrByTag(cat, "id"))
+    if err != nil {
+        return nil
+    }
+
+    return &AdvertCategoryNode{
+        Element: cat,
+        ID:      catID,
+        Slug: u.FallbackStringWithReport(
+            u.ExtractText(cat, "./cat:id-name"))(
+            "", reporter, "ads/ad/category/slug"),
+        ParentSlug: u.FallbackStringWithReport(
+            u.ExtractText(cat, "./cat:l1-name"))(
+            "", reporter, "ads/ad/category/parent_slug"),
+        ChildrenCount: u.FallbackUintWithReport(
+            u.ExtractTextAsUint(cat, "./cat:children-count"))(
+            0, reporter, "ads/ad/category/children_count"),
+    }
+}
+
+func parsePosition(ad *etree.Element, reporter Reporter) PositionNode {
+    var position PositionNode
+
+    position.Address, _ = u.ExtractText(ad, "./ad:ad-address/types:full-address")
+    position.City, _ = u.ExtractText(ad, "./ad:ad-address/types:city")
+    position.State, _ = u.ExtractText(ad, "./ad:ad-address/types:state")
+    position.Country, _ = u.ExtractText(ad, "./ad:ad-address/types:country")
+
+    longitude, longerr := u.ConvString2Float64(u.ExtractText(ad, "./ad:ad-address/types:longitude"))
+    latitude, laterr := u.ConvString2Float64(u.ExtractText(ad, "./ad:ad-address/types:latitude"))
+
+    if longerr == nil && laterr == nil {
+        position.Coordinate = &CoordinateNode{
+            Longitude: longitude,
+            Latitude:  latitude,
+        }
+    } else if longerr != nil {
+        reporter.Report("ads/ad/positions/coordinate", longerr)
+    } else {
+        reporter.Report("ads/ad/positions/coordinate", laterr)
+    }
+
+    for i, loc := range ad.FindElements("./loc:locations/loc:location") {
+        locID := u.FallbackUintWithReport(
+            u.ConvString2Uint(u.ExtractAttrByTag(loc, "id")))(
+            0, reporter, fmt.Sprintf("ads/ad/positions/locations[%d]/id", i))
+
+        locParentID, _ := u.ConvString2Uint(u.ExtractText(loc, "./loc:parent-id"))
+
+        position.Locations = append(position.Locations, LocationNode{
+            Element:  loc,
+            ID:       locID,
+            ParentID: locParentID,
+        })
+    }
+
+    return position
+}
+
+// parseTimestamp parses an RFC 3339 time in UTC, returning nil if the element is missing or invalid
+func parseTimestamp(element *etree.Element) *time.Time {
+    if element != nil {
+        timestr := element.Text()
+
+        if timeinst, err := time.Parse(time.RFC3339, timestr); err == nil {
+            timeinst = timeinst.UTC()
+            return &timeinst
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,144 @@
+package parsers
+
+import (
+    "fmt"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+)
+
+// ErrorDocument is the content of an `api-base-error` response
+type ErrorDocument struct {
+    StatusCode              uint                `json:"status_code,omitempty"`
+    Errors                  []ErrorDetail       `json:"errors,omitempty"`
+    FieldErrors             []FieldError        `json:"field_errors,omitempty"`
+}
+
+// ErrorDetail is an error not related to a specific field
+type ErrorDetail struct {
+    Code                    string              `json:"code,omitempty"`
+    Message                 string              `json:"message"`
+    LocalizedMessage        string              `json:"localized_message,omitempty"`
+}
+
+// FieldError is a validation error of a field in the request, e.g. the title of a posted ad
+type FieldError struct {
+    Field                   string              `json:"field"`
+    Code                    string              `json:"code,omitempty"`
+    Message                 string              `json:"message"`
+    LocalizedMessage        string              `json:"localized_message,omitempty"`
+}
+
+func (e FieldError) String() string {
+    return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// Code returns the code of the first error, or an empty string if there is none
+func (doc *ErrorDocument) Code() string {
+    for _, e := range doc.Errors {
+        if e.Code != "" {
+            return e.Code
+        }
+    }
+
+    for _, e := range doc.FieldErrors {
+        if e.Code != "" {
+            return e.Code
+        }
+    }
+
+    return ""
+}
+
+// Message returns the message of the first error, or an empty string if there is none
+func (doc *ErrorDocument) Message() string {
+    for _, e := range doc.Errors {
+        if e.Message != "" {
+            return e.Message
+        }
+    }
+
+    if len(doc.FieldErrors) > 0 {
+        return "Invalid field values"
+    }
+
+    return ""
+}
+
+// String summarises the error document in a single line
+func (doc *ErrorDocument) String() string {
+    var fields []string
+
+    for _, e := range doc.FieldErrors {
+        fields = append(fields, e.String())
+    }
+
+    if len(fields) < 1 {
+        return doc.Message()
+    }
+
+    return fmt.Sprintf("%s (%s)", doc.Message(), strings.Join(fields, "; "))
+}
+
+// ParseErrorDocument is to build an ErrorDocument model from an `api-base-error` response.
+// It returns nil if the document is not an error document.
+func ParseErrorDocument(doc *etree.Document) *ErrorDocument {
+    if doc == nil {
+        return nil
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Tag != "api-base-error" {
+        return nil
+    }
+
+    statusCode, _ := u.ConvString2Uint(u.ExtractAttrByTag(root, "http-status-code"))
+
+    errorDoc := &ErrorDocument{StatusCode: statusCode}
+
+    for _, element := range root.FindElements(".//api-errors/api-error") {
+        errorDoc.Errors = append(errorDoc.Errors, ErrorDetail{
+            Code:             extractCode(element),
+            Message:          extractTrimmedText(element, "./message"),
+            LocalizedMessage: extractTrimmedText(element, "./localized-message"),
+        })
+    }
+
+    for _, element := range root.FindElements(".//api-field-errors/api-field-error") {
+        errorDoc.FieldErrors = append(errorDoc.FieldErrors, FieldError{
+            Field:            extractTrimmedText(element, "./field"),
+            Code:             extractCode(element),
+            Message:          extractTrimmedText(element, "./message"),
+            LocalizedMessage: extractTrimmedText(element, "./localized-message"),
+        })
+    }
+
+    if len(errorDoc.Errors) < 1 && len(errorDoc.FieldErrors) < 1 { // simple error documents only carry a message
+        if message := extractTrimmedText(root, "//message"); message != "" {
+            errorDoc.Errors = append(errorDoc.Errors, ErrorDetail{
+                Code:    extractCode(root),
+                Message: message,
+            })
+        }
+    }
+
+    return errorDoc
+}
+
+func extractCode(element *etree.Element) string {
+    if code := extractTrimmedText(element, "./error-code"); code != "" {
+        return code
+    }
+
+    if code, err := u.ExtractAttrByTag(element, "code"); err == nil {
+        return strings.TrimSpace(code)
+    }
+
+    return ""
+}
+
+func extractTrimmedText(element *etree.Element, path string) string {
+    text, _ := u.ExtractText(element, path)
+    return strings.TrimSpace(text)
+}
@@ -2,114 +2,110 @@ package auparser
 
 import (
     "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
     models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
     u "github.com/GreenVine/ebay-classifieds-api/utils"
     "github.com/beevik/etree"
-    "strings"
-    "time"
 )
 
-// ParseAdvert is to build a Category models from raw XML response
-func ParseAdvert(doc *etree.Document) (*models.Advert, []error, bool) {
+// RequiredFields are the fields rejecting an advertisement in strict mode if missing or invalid,
+// rather than being replaced with fallback values such as price type `UNKNOWN` and amount 0
+var RequiredFields = []string{
+    "ads/ad/title",
+    "ads/ad/price/type",
+    "ads/ad/price/amount",
+    "ads/ad/price/currency",
+}
+
+// ParseAdvert is to build an Advert model from raw XML response, leniently unless options say otherwise
+func ParseAdvert(doc *etree.Document, opts ...parsers.Option) (*models.Advert, *parsers.ParseReport) {
+    report := parsers.NewParseReport(RequiredFields, opts...)
+
     if doc == nil {
-        return nil, []error{ fmt.Errorf("empty API response") }, true
+        report.Reporter(0).Fatal("", parsers.ErrEmptyResponse)
+        return nil, report
     }
 
     root := doc.Root()
 
     if root == nil || root.Space != "ad" || root.Tag != "ad" {
-        return nil, []error{ fmt.Errorf("unexpected API response") }, true
+        report.Reporter(0).Fatal("ads/ad", parsers.ErrUnexpectedResponse)
+        return nil, report
     }
 
-    var errors []error
-    var hasCriticalError = false
+    if advert := BuildAdvertBase(root, report); advert != nil && !report.IsFatal() {
+        return advert, report
+    }
 
-    if advert := BuildAdvertBase(root, &errors, &hasCriticalError); !hasCriticalError {
-        return advert, errors, false
+    if !report.IsFatal() { // a single advertisement that was skipped leaves nothing to return
+        report.Reporter(0).Fatal("ads/ad", fmt.Errorf("advertisement could not be built"))
     }
 
-    return nil, errors, true
+    return nil, report
 }
 
 // BuildAdvertBase is to build the base of an advertisement
-func BuildAdvertBase(ad *etree.Element, errors *[]error, hasCriticalError *bool) *models.Advert {
-    advertID, err := u.ConvString2Uint(u.ExtractAttrByTag(ad, "id"))
-    if err != nil {
-        *errors = append(*errors, fmt.Errorf("ads/ad/id"))
+func BuildAdvertBase(ad *etree.Element, report *parsers.ParseReport) *models.Advert {
+    node := parsers.ParseAdvertNode(ad, report, report.Options())
+    if node == nil {
         return nil
     }
 
-    advertType, _ := u.ExtractText(ad, "./ad:ad-type/ad:value")
-
-    advertUserID, _ := u.ConvString2Uint(u.ExtractText(ad, "./ad:user-id"))
-
-    advertPrice := buildPrice(ad, errors, hasCriticalError)
-
-    advertStatus, _ := u.ExtractText(ad, "./ad:ad-status/ad:value")
-
-    advertContact := buildContact(ad, errors, hasCriticalError)
-
-    advertCategory := buildCategory(ad, errors, hasCriticalError)
-
-    advertPosition := buildPosition(ad, errors, hasCriticalError)
-
-    advertPosterType, _ := u.ExtractText(ad, "./ad:poster-type/ad:value")
+    reporter := node.Reporter
 
-    advertTitle := u.FallbackStringWithReport(
-        u.ExtractText(ad, "./ad:title"))(
-        "", errors, fmt.Errorf("ads/ad/title"))
+    advertPrice := buildPrice(ad, reporter)
 
-    advertDescriptionExcerptHTML := u.FallbackStringWithReport(
-        u.ExtractText(ad, "./ad:description"))(
-        "", errors, fmt.Errorf("ads/ad/desc_excerpt_html"))
+    advertPictures := buildPicture(ad, reporter)
 
-    advertDescriptionExcerpt, _ := u.FormatHTML2Base64(advertDescriptionExcerptHTML)
+    advertAttributes := buildAttribute(ad, reporter)
 
-    advertPictures := buildPicture(ad, errors, hasCriticalError)
-
-    advertAttributes := buildAttribute(ad, errors, hasCriticalError)
-    advertTimestamp := buildTimestamp(ad, errors, hasCriticalError)
+    if report.Skipped(node.ID) { // a required field is missing
+        return nil
+    }
 
     return &models.Advert{
-        ID:         advertID,
-        Type:       u.ReplaceStringWithNil(&advertType, ""),
-        UserID:     u.ReplaceUintWithNil(&advertUserID, 0),
-        Status:     u.ReplaceStringWithNil(&advertStatus, ""),
-        Contact:    advertContact,
-        Category:   advertCategory,
-        Position:   advertPosition,
-        PosterType: u.ReplaceStringWithNil(&advertPosterType, ""),
+        ID:         node.ID,
+        Type:       u.ReplaceStringWithNil(&node.Type, ""),
+        UserID:     u.ReplaceUintWithNil(&node.UserID, 0),
+        Status:     u.ReplaceStringWithNil(&node.Status, ""),
+        Contact:    buildContact(node.Contact),
+        Category:   buildCategory(node.Category, reporter),
+        Position:   buildPosition(node.Position, reporter),
+        PosterType: u.ReplaceStringWithNil(&node.PosterType, ""),
         Price:      advertPrice,
-        Title:      *u.ReplaceStringWithNil(&advertTitle, ""),
-        DescriptionExcerptB64:  u.ReplaceStringWithNil(&advertDescriptionExcerpt, ""),
-        DescriptionExcerptHTML: u.ReplaceStringWithNil(&advertDescriptionExcerptHTML, ""),
+        Title:      node.Title,
+        DescriptionExcerptB64:  node.Description.Base64,
+        DescriptionExcerptHTML: node.Description.RawHTML,
+        Description:            node.Description.Text,
+        DescriptionHTML:        node.Description.SafeHTML,
         Pictures:               advertPictures,
         Attributes:             advertAttributes,
-        Timestamp:              advertTimestamp,
+        Timestamp:              buildTimestamp(node.Timestamp),
     }
 }
 
-func buildPrice(ad *etree.Element, errors *[]error, _ *bool) *models.AdvertPrice {
+func buildPrice(ad *etree.Element, reporter parsers.Reporter) *models.AdvertPrice {
     priceType := u.FallbackStringWithReport(
         u.ExtractText(ad, "./ad:price/types:price-type/types:value"))(
-        "UNKNOWN", errors, fmt.Errorf("ads/ad/price/type"))
-
-    priceAmount := uint(u.FallbackFloat64WithReport(
-        u.ExtractTextAsFloat64(ad, "./ad:price/types:amount"))(
-        0.0, errors, fmt.Errorf("ads/ad/price/amount")) * 100)
-
-    priceHighestAmount := uint(u.FallbackFloat64WithReport(
-        u.ExtractTextAsFloat64(ad, "./ad:highest-price"))(
-        0, errors, fmt.Errorf("ads/ad/price/highest_amount")) * 100)
+        "UNKNOWN", reporter, "ads/ad/price/type")
 
     currency := u.FallbackStringWithReport(
         u.ExtractText(ad, "./ad:price/types:currency-iso-code/types:value"))(
-        "", errors, fmt.Errorf("ads/ad/price/currency"))
+        "", reporter, "ads/ad/price/currency")
+
+    priceAmount := u.FallbackAmountWithReport(
+        u.ExtractTextAsAmount(ad, "./ad:price/types:amount", currency))(
+        money.New(0, currency), reporter, "ads/ad/price/amount")
+
+    priceHighestAmount := u.FallbackAmountWithReport(
+        u.ExtractTextAsAmount(ad, "./ad:highest-price", currency))(
+        money.New(0, currency), reporter, "ads/ad/price/highest_amount")
 
     currencySymbol := u.FallbackStringWithReport(
         u.ExtractAttrByTag(ad.FindElement(
             "./ad:price/types:currency-iso-code/types:value"), "localized-label"))(
-        "", errors, fmt.Errorf("ads/ad/price/currency_symbol"))
+        "", reporter, "ads/ad/price/currency_symbol")
 
     return &models.AdvertPrice{
         Type: &priceType,
@@ -120,94 +116,58 @@ func buildPrice(ad *etree.Element, errors *[]error, _ *bool) *models.AdvertPrice
     }
 }
 
-func buildCategory(ad *etree.Element, errors *[]error, _ *bool) *models.AdvertCategory {
-    cat := ad.FindElement("./cat:category")
+func buildCategory(cat *parsers.AdvertCategoryNode, reporter parsers.Reporter) *models.AdvertCategory {
     if cat == nil {
         return nil
     }
 
-    catID, err := u.ConvString2Uint(u.ExtractAttrByTag(cat, "id"))
-    if err != nil {
-        return nil
-    }
-
     catName := u.FallbackStringWithReport(
-        u.ExtractText(cat, "./cat:localized-name"))(
-        "", errors, fmt.Errorf("ads/ad/category/name"))
-
-    catSlug := u.FallbackStringWithReport(
-        u.ExtractText(cat, "./cat:id-name"))(
-        "", errors, fmt.Errorf("ads/ad/category/slug"))
-
-    catParentSlug := u.FallbackStringWithReport(
-        u.ExtractText(cat, "./cat:l1-name"))(
-        "", errors, fmt.Errorf("ads/ad/category/parent_slug"))
-
-    catChildrenCount := u.FallbackUintWithReport(
-        u.ExtractTextAsUint(cat, "./cat:children-count"))(
-        0, errors, fmt.Errorf("ads/ad/category/children_count"))
+        u.ExtractText(cat.Element, "./cat:localized-name"))(
+        "", reporter, "ads/ad/category/name")
 
     return &models.AdvertCategory{
-        ID:            catID,
-        Name:          *u.ReplaceStringWithNil(&catName, ""),
-        Slug:          u.ReplaceStringWithNil(&catSlug, ""),
-        ParentSlug:    u.ReplaceStringWithNil(&catParentSlug, ""),
-        ChildrenCount: &catChildrenCount,
+        ID:            cat.ID,
+        Name:          catName,
+        Slug:          u.ReplaceStringWithNil(&cat.Slug, ""),
+        ParentSlug:    u.ReplaceStringWithNil(&cat.ParentSlug, ""),
+        ChildrenCount: &cat.ChildrenCount,
     }
 }
 
-func buildPosition(ad *etree.Element, errors *[]error, _ *bool) *models.AdvertPosition {
+func buildPosition(position parsers.PositionNode, reporter parsers.Reporter) *models.AdvertPosition {
     var coordinate *models.AdvertCoordinate
     var locations []models.AdvertLocation
 
-    address, _ := u.ExtractText(ad, "./ad:ad-address/types:full-address")
-    city, _ := u.ExtractText(ad, "./ad:ad-address/types:city")
-    state, _ := u.ExtractText(ad, "./ad:ad-address/types:state")
-    country, _ := u.ExtractText(ad, "./ad:ad-address/types:country")
-
-    longitude, longerr := u.ConvString2Float64(u.ExtractText(ad, "./ad:ad-address/types:longitude"))
-    latitude, laterr := u.ConvString2Float64(u.ExtractText(ad, "./ad:ad-address/types:latitude"))
-
-    if longerr == nil && laterr == nil {
+    if position.Coordinate != nil {
         coordinate = &models.AdvertCoordinate{
-            Longitude: longitude,
-            Latitude: latitude,
+            Longitude: position.Coordinate.Longitude,
+            Latitude:  position.Coordinate.Latitude,
         }
-    } else {
-        *errors = append(*errors, fmt.Errorf("ads/ad/positions/coordinate"))
     }
 
-    if locs := ad.FindElements("./loc:locations/loc:location"); locs != nil {
-        for i, loc := range locs {
-            locID := u.FallbackUintWithReport(
-               u.ConvString2Uint(u.ExtractAttrByTag(loc, "id")))(
-               0, errors, fmt.Errorf("ads/ad/positions/locations[%d]/id", i))
+    for i, loc := range position.Locations {
+        locName := u.FallbackStringWithReport(
+            u.ExtractText(loc.Element, "./loc:localized-name"))(
+            "", reporter, fmt.Sprintf("ads/ad/positions/location[%d]/name", i))
 
-            locName := u.FallbackStringWithReport(
-              u.ExtractText(loc, "./loc:localized-name"))(
-              "", errors, fmt.Errorf("ads/ad/positions/location[%d]/name", i))
-
-            locParentID, _ := u.ConvString2Uint(u.ExtractText(loc, "./loc:parent-id"))
-
-            locations = append(locations, models.AdvertLocation{
-               ID:         *u.ReplaceUintWithNil(&locID, 0),
-               Name:       *u.ReplaceStringWithNil(&locName, ""),
-               ParentID:   u.ReplaceUintWithNil(&locParentID, 0),
-            })
-        }
+        locations = append(locations, models.AdvertLocation{
+            ID:         loc.ID,
+            Name:       locName,
+            ParentID:   u.ReplaceUintWithNil(&loc.ParentID, 0),
+        })
     }
 
     return &models.AdvertPosition{
-        Address:    u.ReplaceStringWithNil(&address, ""),
-        City:       u.ReplaceStringWithNil(&city, ""),
-        State:      u.ReplaceStringWithNil(&state, ""),
-        Country:    u.ReplaceStringWithNil(&country, ""),
+        Address:    u.ReplaceStringWithNil(&position.Address, ""),
+        City:       u.ReplaceStringWithNil(&position.City, ""),
+        State:      u.ReplaceStringWithNil(&position.State, ""),
+        Country:    u.ReplaceStringWithNil(&position.Country, ""),
         Coordinate: coordinate,
         Locations:  locations,
     }
 }
 
-func buildPicture(ad *etree.Element, errors *[]error, _ *bool) []models.AdvertPicture {
+func buildPicture(ad *etree.Element, reporter parsers.Reporter) []models.AdvertPicture {
     var pictures []models.AdvertPicture
 
     if pics := ad.FindElements("./pic:pictures/pic:picture"); pics != nil {
@@ -215,19 +175,19 @@ func buildPicture(ad *etree.Element, errors *[]error, _ *bool) []models.AdvertPi
             if pic != nil {
                 thumbnail := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(pic.FindElement("./pic:link[@rel='thumbnail']"), "href"))(
-                    "", errors, fmt.Errorf("ads/ad/pictures[%d]/thumbnail", i))
+                    "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/thumbnail", i))
                 normal := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(pic.FindElement("./pic:link[@rel='normal']"), "href"))(
-                    "", errors, fmt.Errorf("ads/ad/pictures[%d]/normal", i))
+                    "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/normal", i))
                 large := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(pic.FindElement("./pic:link[@rel='large']"), "href"))(
-                    "", errors, fmt.Errorf("ads/ad/pictures[%d]/large", i))
+                    "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/large", i))
                 extraLarge := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(pic.FindElement("./pic:link[@rel='extraLarge']"), "href"))(
-                    "", errors, fmt.Errorf("ads/ad/pictures[%d]/extraLarge", i))
+                    "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/extraLarge", i))
                 extra2XLarge := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(pic.FindElement("./pic:link[@rel='extraExtraLarge']"), "href"))(
-                    "", errors, fmt.Errorf("ads/ad/pictures[%d]/extra2XLarge", i))
+                    "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/extra2XLarge", i))
 
                 pictures = append(pictures, models.AdvertPicture{
                     Thumbnail:    u.ReplaceStringWithNil(&thumbnail, ""),
@@ -243,7 +203,7 @@ func buildPicture(ad *etree.Element, errors *[]error, _ *bool) []models.AdvertPi
     return pictures
 }
 
-func buildAttribute(ad *etree.Element, errors *[]error, _ *bool) []models.AdvertAttribute {
+func buildAttribute(ad *etree.Element, reporter parsers.Reporter) []models.AdvertAttribute {
     var attributes []models.AdvertAttribute
 
     if attrs := ad.FindElements("./attr:attributes/attr:attribute"); attrs != nil {
@@ -251,26 +211,29 @@ func buildAttribute(ad *etree.Element, errors *[]error, _ *bool) []models.Advert
             if attr != nil {
                 keySlug := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(attr, "name"))(
-                    "", errors, fmt.Errorf("ads/ad/attributes[%d]/key_slug", i))
+                    "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_slug", i))
                 keyName := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(attr, "localized-label"))(
-                    "", errors, fmt.Errorf("ads/ad/attributes[%d]/key_name", i))
+                    "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_name", i))
                 valueType := u.FallbackStringWithReport(
                     u.ExtractAttrByTag(attr, "type"))(
-                    "", errors, fmt.Errorf("ads/ad/attributes[%d]/value_type", i))
+                    "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/value_type", i))
 
-                valueSlugStr, _ := u.ExtractText(attr, "./attr:value")
-                valueSlug := u.ReplaceStringWithNil(&valueSlugStr, "")
+                values := buildAttributeValues(attr, valueType, fmt.Sprintf("ads/ad/attributes[%d]", i), reporter)
 
-                valueNameStr, _ := u.ExtractAttrByTag(attr.FindElement("./attr:value"), "localized-label")
-                valueName := u.ReplaceStringWithNil(&valueNameStr, "")
+                var valueSlug, valueName *string
+                if len(values) > 0 { // the first value for attributes with a single value
+                    valueSlug = u.ReplaceStringWithNil(&values[0].Slug, "")
+                    valueName = u.ReplaceStringWithNil(&values[0].Label, "")
+                }
 
                 attributes = append(attributes, models.AdvertAttribute{
                     KeySlug:    *u.ReplaceStringWithNil(&keySlug, ""),
                     KeyName:    *u.ReplaceStringWithNil(&keyName, ""),
                     ValueType:  u.ReplaceStringWithNil(&valueType, ""),
-                    ValueSlug:  u.ReplaceStringWithNil(valueSlug, ""),
-                    ValueName:  u.ReplaceStringWithNil(valueName, ""),
+                    ValueSlug:  valueSlug,
+                    ValueName:  valueName,
+                    Values:     values,
                 })
             }
         }
@@ -279,40 +242,40 @@ func buildAttribute(ad *etree.Element, errors *[]error, _ *bool) []models.Advert
     return attributes
 }
 
-func buildTimestamp(ad *etree.Element, _ *[]error, _ *bool) models.AdvertTimestamp {
-    advertCreationTime := formatTimestamp(ad.FindElement("./ad:creation-date-time"))
-    advertModificationTime := formatTimestamp(ad.FindElement("./ad:modification-date-time"))
-    advertStartTime := formatTimestamp(ad.FindElement("./ad:start-date-time"))
-    advertEndTime := formatTimestamp(ad.FindElement("./ad:end-date-time"))
+// buildAttributeValues builds every value of an attribute, reporting values that do not convert to the value type.
+// Such values are kept as text, so that `AdvertAttribute.Value` returns the conversion error again.
+func buildAttributeValues(attr *etree.Element, valueType string, path string, reporter parsers.Reporter) []models.AttributeValue {
+    var values []models.AttributeValue
 
-    return models.AdvertTimestamp{
-        CreationTime:       advertCreationTime,
-        ModificationTime:   advertModificationTime,
-        StartTime:          advertStartTime,
-        EndTime:            advertEndTime,
-    }
-}
+    for i, value := range attr.SelectElements("value") {
+        slug := value.Text()
+        label, _ := u.ExtractAttrByTag(value, "localized-label")
 
-func buildContact(ad *etree.Element, _ *[]error, _ *bool) *models.AdvertContact {
-    name, _  := u.ExtractText(ad, "./ad:poster-contact-name")
-    phone, _ := u.ExtractText(ad, "./ad:phone")
-    phone     = strings.Replace(phone, " ", "", -1)
+        if _, err := models.ConvertAttributeValue(valueType, slug); err != nil {
+            reporter.Report(fmt.Sprintf("%s/values[%d]", path, i), err)
+        }
 
-    return &models.AdvertContact{
-        Name:   u.ReplaceStringWithNil(&name, ""),
-        Phone:  u.ReplaceStringWithNil(&phone, ""),
+        values = append(values, models.AttributeValue{
+            Slug:  slug,
+            Label: label,
+        })
     }
-}
 
-func formatTimestamp(element *etree.Element) *time.Time {
-    if element != nil {
-        timestr := element.Text()
+    return values
+}
 
-        if timeinst, err := time.Parse(time.RFC3339, timestr); err == nil {
-            timeinst = timeinst.UTC()
-            return &timeinst
-        }
+func buildTimestamp(timestamp parsers.TimestampNode) models.AdvertTimestamp {
+    return models.AdvertTimestamp{
+        CreationTime:       timestamp.CreationTime,
+        ModificationTime:   timestamp.ModificationTime,
+        StartTime:          timestamp.StartTime,
+        EndTime:            timestamp.EndTime,
     }
+}
 
-    return nil
+func buildContact(contact parsers.ContactNode) *models.AdvertContact {
+    return &models.AdvertContact{
+        Name:   u.ReplaceStringWithNil(&contact.Name, ""),
+        Phone:  u.ReplaceStringWithNil(&contact.Phone, ""),
+    }
 }
@@ -0,0 +1,103 @@
+package auparser
+
+import (
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+)
+
+// AttributeRequiredFields are the fields rejecting an attribute schema in strict mode if missing or invalid
+var AttributeRequiredFields = []string{
+    "attributes/category_id",
+    "attributes/attribute/name",
+}
+
+// ParseAttributeSchema is to build the attribute metadata of a category from raw XML response,
+// leniently unless options say otherwise. Attributes without a name are left out of the schema.
+func ParseAttributeSchema(doc *etree.Document, opts ...parsers.Option) (*models.AttributeSchema, *parsers.ParseReport) {
+    report := parsers.NewParseReport(AttributeRequiredFields, opts...)
+    reporter := report.Reporter(0)
+
+    if doc == nil {
+        reporter.Fatal("", parsers.ErrEmptyResponse)
+        return nil, report
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "attr" || root.Tag != "attributes" {
+        reporter.Fatal("attributes", parsers.ErrUnexpectedResponse)
+        return nil, report
+    }
+
+    categoryID, err := u.ConvString2Uint(u.ExtractAttrByTag(root, "category-id"))
+    if err != nil {
+        reporter.Fatal("attributes/category_id", err)
+        return nil, report
+    }
+
+    var definitions []models.AttributeDefinition
+
+    for i, attribute := range root.SelectElements("attribute") {
+        if definition := buildAttributeDefinition(attribute, fmt.Sprintf("attributes/attribute[%d]", i), reporter); definition != nil {
+            definitions = append(definitions, *definition)
+        }
+    }
+
+    return &models.AttributeSchema{
+        CategoryID: categoryID,
+        Attributes: definitions,
+    }, report
+}
+
+func buildAttributeDefinition(attribute *etree.Element, path string, reporter parsers.Reporter) *models.AttributeDefinition {
+    name, err := u.ExtractAttrByTag(attribute, "name")
+    if err == nil && name == "" {
+        err = u.ErrAttributeMissing
+    }
+
+    if err != nil {
+        reporter.Report(path + "/name", err)
+        return nil
+    }
+
+    label := u.FallbackStringWithReport(
+        u.ExtractAttrByTag(attribute, "localized-label"))(
+        name, reporter, fmt.Sprintf("attributes/attribute/%s/label", name))
+    valueType := u.FallbackStringWithReport(
+        u.ExtractAttrByTag(attribute, "type"))(
+        models.AttributeTypeString, reporter, fmt.Sprintf("attributes/attribute/%s/type", name))
+
+    unit, _ := u.ExtractText(attribute, "./attr:unit")
+    write, _ := u.ExtractAttrByTag(attribute, "write")
+    searchable, _ := u.ExtractAttrByTag(attribute, "searchable")
+
+    var values []models.AttributeValue
+
+    for _, value := range attribute.SelectElements("supported-value") {
+        slug := strings.TrimSpace(value.Text())
+        if slug == "" {
+            continue
+        }
+
+        label, _ := u.ExtractAttrByTag(value, "localized-label")
+
+        values = append(values, models.AttributeValue{
+            Slug:  slug,
+            Label: label,
+        })
+    }
+
+    return &models.AttributeDefinition{
+        Name:       name,
+        Label:      label,
+        Type:       strings.ToUpper(valueType),
+        Unit:       u.ReplaceStringWithNil(&unit, ""),
+        Required:   strings.EqualFold(write, "required"),
+        Searchable: strings.EqualFold(searchable, "true"),
+        Values:     values,
+    }
+}
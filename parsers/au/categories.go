@@ -1,99 +1,64 @@
 package auparser
 
 import (
-    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
     models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
     u "github.com/GreenVine/ebay-classifieds-api/utils"
     "github.com/beevik/etree"
 )
 
-// ParseCategories is to build a Categories model from raw XML response
-func ParseCategories(doc *etree.Document) (*models.Categories, []error, bool) {
-    if doc == nil {
-        return nil, []error{ fmt.Errorf("empty API response") }, true
-    }
-
-    var errors []error
-    var hasCriticalError = false
-
-    root := doc.Root()
+// CategoryRequiredFields are the fields rejecting a category response in strict mode if missing or invalid,
+// including those of subcategories
+var CategoryRequiredFields = []string{
+    "categories/category/id",
+    "categories/category/name",
+}
 
-    if root == nil || root.Space != "cat" {
-       return nil, []error{ fmt.Errorf("unexpected API response") }, true
-    } else {
-        var rootCategory *etree.Element
+// ParseCategories is to build every category of a raw XML response as a forest in document order, leniently unless options say otherwise.
+// A `cat:categories` response may hold several top-level categories, each being the root of its own tree.
+func ParseCategories(doc *etree.Document, opts ...parsers.Option) ([]models.Categories, *parsers.ParseReport) {
+    report := parsers.NewParseReport(CategoryRequiredFields, opts...)
+    reporter := report.Reporter(0)
 
-        switch root.Tag {
-        case "categories": { // contains multiple categories
-            rootCategory = root.SelectElement("category")
-        }
-        case "category": { // contains a single category
-            rootCategory = root
-        }
-        default:
-            return nil, []error{ fmt.Errorf("unexpected API category response") }, true
-        }
-
-        if rootCategory != nil {
-            if categories := buildCategories(rootCategory, &errors, &hasCriticalError); !hasCriticalError && categories != nil {
-                return categories, errors, false
-            }
-        }
+    nodes := parsers.ParseCategoryForest(doc, reporter)
+    if report.IsFatal() {
+        return nil, report
     }
 
-
-
-    return nil, errors, true
+    return buildCategories(nodes, reporter), report
 }
 
-func buildCategories(category *etree.Element, errors *[]error, hasCriticalError *bool) *models.Categories {
-    if category != nil {
-        catID, err := u.ConvString2Uint(u.ExtractAttrByTag(category, "id"))
-        if err != nil {
-           return nil
-        }
+// buildCategories builds the models of sibling categories and their subcategories.
+// Parents are always set, missing ones being replaced with zero values.
+func buildCategories(nodes []parsers.CategoryNode, reporter parsers.Reporter) []models.Categories {
+    var categories []models.Categories
 
+    for _, node := range nodes {
         catName := u.FallbackStringWithReport(
-           u.ExtractText(category, "./cat:localized-name"))(
-           "", errors, fmt.Errorf("categories/category/%d/name", catID))
-
-        catSlug := u.FallbackStringWithReport(
-           u.ExtractText(category, "./cat:id-name"))(
-           "", errors, fmt.Errorf("categories/category/%d/slug", catID))
-
-        catParentID := u.FallbackUintWithReport(
-            u.ExtractTextAsUint(category, "./cat:parent-id"))(
-            0, errors, fmt.Errorf("categories/category/%d/parent_id", catID))
+            u.ExtractText(node.Element, "./cat:localized-name"))(
+            "", reporter, node.Path() + "/name")
 
-        catParentSlug := u.FallbackStringWithReport(
-           u.ExtractText(category, "./cat:l1-name"))(
-           "", errors, fmt.Errorf("categories/category/%d/parent_slug", catID))
+        catParentID, catParentSlug := node.ParentID, node.ParentSlug
 
-        catChildrenCount := u.FallbackUintWithReport(
-           u.ExtractTextAsUint(category, "./cat:children-count"))(
-           0, errors, fmt.Errorf("categories/category/%d/children_count", catID))
-
-        var subcategories []models.Categories
-
-        if subcategoriesList := category.FindElements("./cat:category"); subcategoriesList != nil {
-            // recursively add subcategories
+        if catParentID == nil {
+            catParentID = new(uint)
+        }
 
-            for _, subcategory := range subcategoriesList {
-                subcategories = append(subcategories, *buildCategories(subcategory, errors, hasCriticalError))
-            }
+        if catParentSlug == nil {
+            catParentSlug = new(string)
         }
 
-        return &models.Categories{
-            ID:             catID,
+        categories = append(categories, models.Categories{
+            ID:             node.ID,
             Name:           catName,
-            Slug:           catSlug,
-            ParentID:       &catParentID,
-            ParentSlug:     &catParentSlug,
-            ChildrenCount:  catChildrenCount,
-            Subcategories:  subcategories,
-            IsRootCategory: catID <= 0,
-        }
+            Slug:           node.Slug,
+            ParentID:       catParentID,
+            ParentSlug:     catParentSlug,
+            ChildrenCount:  node.ChildrenCount,
+            Subcategories:  buildCategories(node.Subcategories, reporter),
+            IsRootCategory: node.IsRoot,
+        })
     }
 
-    return nil
+    return categories
 }
@@ -0,0 +1,293 @@
+package auparser
+
+import (
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+    "html"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// ErrNilAdvert is returned when encoding a nil advertisement
+var ErrNilAdvert = errors.New("auparser: nil advert")
+
+// EncodeOption configures the document built by EncodeAdvert
+type EncodeOption func(*encodeOptions)
+
+type encodeOptions struct {
+    serverFields bool
+}
+
+// IncludeServerFields encodes the fields assigned by the API, i.e. the ID, status, user ID and timestamps,
+// which are left out of create payloads by default
+func IncludeServerFields() EncodeOption {
+    return func(o *encodeOptions) {
+        o.serverFields = true
+    }
+}
+
+// EncodeAdvert is to build an ECG `ad:ad` document from an Advert model, the reverse of ParseAdvert.
+// Fields assigned by the API are left out unless `IncludeServerFields` is given, which suits create payloads,
+// and fields that are nil, empty or zero IDs are always left out.
+// The description is taken from the raw HTML, else the sanitised HTML, else the plain text.
+func EncodeAdvert(advert *models.Advert, opts ...EncodeOption) (*etree.Document, error) {
+    if advert == nil {
+        return nil, ErrNilAdvert
+    }
+
+    var options encodeOptions
+    for _, opt := range opts {
+        opt(&options)
+    }
+
+    doc := etree.NewDocument()
+    doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8" standalone="yes"`)
+
+    ad := doc.CreateElement("ad:ad")
+    ad.CreateAttr("xmlns:ad", parsers.NamespaceAd)
+    ad.CreateAttr("xmlns:cat", parsers.NamespaceCategory)
+    ad.CreateAttr("xmlns:loc", parsers.NamespaceLocation)
+    ad.CreateAttr("xmlns:attr", parsers.NamespaceAttribute)
+    ad.CreateAttr("xmlns:types", parsers.NamespaceTypes)
+    ad.CreateAttr("xmlns:pic", parsers.NamespacePicture)
+
+    if options.serverFields && advert.ID > 0 {
+        ad.CreateAttr("id", strconv.FormatUint(uint64(advert.ID), 10))
+    }
+
+    ad.CreateElement("ad:title").SetText(advert.Title)
+
+    if description := encodeDescription(advert); description != "" {
+        ad.CreateElement("ad:description").SetText(description)
+    }
+
+    createValue(ad, "ad:ad-type", "ad:value", advert.Type)
+
+    if options.serverFields {
+        createValue(ad, "ad:ad-status", "ad:value", advert.Status)
+    }
+
+    createValue(ad, "ad:poster-type", "ad:value", advert.PosterType)
+
+    if options.serverFields && advert.UserID != nil {
+        ad.CreateElement("ad:user-id").SetText(strconv.FormatUint(uint64(*advert.UserID), 10))
+    }
+
+    if contact := advert.Contact; contact != nil {
+        createText(ad, "ad:poster-contact-name", contact.Name)
+        createText(ad, "ad:phone", contact.Phone)
+    }
+
+    encodePrice(ad, advert.Price)
+    encodePosition(ad, advert.Position)
+    encodeCategory(ad, advert.Category)
+    encodeAttributes(ad, advert.Attributes)
+    encodePictures(ad, advert.Pictures)
+
+    if options.serverFields {
+        createTime(ad, "ad:creation-date-time", advert.Timestamp.CreationTime)
+        createTime(ad, "ad:modification-date-time", advert.Timestamp.ModificationTime)
+        createTime(ad, "ad:start-date-time", advert.Timestamp.StartTime)
+        createTime(ad, "ad:end-date-time", advert.Timestamp.EndTime)
+    }
+
+    doc.Indent(4)
+
+    return doc, nil
+}
+
+func encodeDescription(advert *models.Advert) string {
+    switch {
+    case advert.DescriptionExcerptHTML != nil:
+        return *advert.DescriptionExcerptHTML
+    case advert.DescriptionHTML != nil:
+        return *advert.DescriptionHTML
+    case advert.Description != nil: // plain text with its line breaks
+        return strings.Replace(html.EscapeString(*advert.Description), "\n", "<br/>", -1)
+    default:
+        return ""
+    }
+}
+
+func encodePrice(ad *etree.Element, price *models.AdvertPrice) {
+    if price == nil {
+        return
+    }
+
+    element := ad.CreateElement("ad:price")
+
+    if price.Currency != nil && *price.Currency != "" {
+        currency := element.CreateElement("types:currency-iso-code").CreateElement("types:value")
+        currency.SetText(*price.Currency)
+
+        if price.CurrencySymbol != nil && *price.CurrencySymbol != "" {
+            currency.CreateAttr("localized-label", *price.CurrencySymbol)
+        }
+    }
+
+    if price.Amount != nil {
+        element.CreateElement("types:amount").SetText(price.Amount.Decimal())
+    }
+
+    createValue(element, "types:price-type", "types:value", price.Type)
+
+    if price.HighestAmount != nil && !price.HighestAmount.IsZero() {
+        ad.CreateElement("ad:highest-price").SetText(price.HighestAmount.Decimal())
+    }
+
+    removeEmpty(ad, element)
+}
+
+func encodePosition(ad *etree.Element, position *models.AdvertPosition) {
+    if position == nil {
+        return
+    }
+
+    address := ad.CreateElement("ad:ad-address")
+    createText(address, "types:full-address", position.Address)
+    createText(address, "types:city", position.City)
+    createText(address, "types:state", position.State)
+    createText(address, "types:country", position.Country)
+
+    if coordinate := position.Coordinate; coordinate != nil {
+        address.CreateElement("types:latitude").SetText(strconv.FormatFloat(coordinate.Latitude, 'f', -1, 64))
+        address.CreateElement("types:longitude").SetText(strconv.FormatFloat(coordinate.Longitude, 'f', -1, 64))
+    }
+
+    removeEmpty(ad, address)
+
+    if len(position.Locations) < 1 {
+        return
+    }
+
+    locations := ad.CreateElement("loc:locations")
+
+    for _, location := range position.Locations {
+        element := locations.CreateElement("loc:location")
+        element.CreateAttr("id", strconv.FormatUint(uint64(location.ID), 10))
+        createText(element, "loc:localized-name", &location.Name)
+
+        if location.ParentID != nil && *location.ParentID > 0 {
+            element.CreateElement("loc:parent-id").SetText(strconv.FormatUint(uint64(*location.ParentID), 10))
+        }
+    }
+}
+
+func encodeCategory(ad *etree.Element, category *models.AdvertCategory) {
+    if category == nil {
+        return
+    }
+
+    element := ad.CreateElement("cat:category")
+    element.CreateAttr("id", strconv.FormatUint(uint64(category.ID), 10))
+    createText(element, "cat:id-name", category.Slug)
+    if category.Name != "" {
+        element.CreateElement("cat:localized-name").SetText(category.Name)
+    }
+    createText(element, "cat:l1-name", category.ParentSlug)
+
+    if category.ChildrenCount != nil {
+        element.CreateElement("cat:children-count").SetText(strconv.FormatUint(uint64(*category.ChildrenCount), 10))
+    }
+}
+
+func encodeAttributes(ad *etree.Element, attributes []models.AdvertAttribute) {
+    if len(attributes) < 1 {
+        return
+    }
+
+    element := ad.CreateElement("attr:attributes")
+
+    for _, attribute := range attributes {
+        attr := element.CreateElement("attr:attribute")
+        attr.CreateAttr("name", attribute.KeySlug)
+
+        if attribute.ValueType != nil && *attribute.ValueType != "" {
+            attr.CreateAttr("type", *attribute.ValueType)
+        }
+
+        if attribute.KeyName != "" {
+            attr.CreateAttr("localized-label", attribute.KeyName)
+        }
+
+        values := attribute.Values
+        if len(values) < 1 && attribute.ValueSlug != nil { // attributes built with a single value only
+            values = []models.AttributeValue{{Slug: *attribute.ValueSlug}}
+
+            if attribute.ValueName != nil {
+                values[0].Label = *attribute.ValueName
+            }
+        }
+
+        for _, value := range values {
+            valueElement := attr.CreateElement("attr:value")
+            valueElement.SetText(value.Slug)
+
+            if value.Label != "" {
+                valueElement.CreateAttr("localized-label", value.Label)
+            }
+        }
+    }
+}
+
+func encodePictures(ad *etree.Element, pictures []models.AdvertPicture) {
+    if len(pictures) < 1 {
+        return
+    }
+
+    element := ad.CreateElement("pic:pictures")
+
+    for _, picture := range pictures {
+        pic := element.CreateElement("pic:picture")
+
+        for _, link := range []struct {
+            rel  string
+            href *string
+        }{
+            {"thumbnail", picture.Thumbnail},
+            {"normal", picture.Normal},
+            {"large", picture.Large},
+            {"extraLarge", picture.ExtraLarge},
+            {"extraExtraLarge", picture.Extra2XLarge},
+        } {
+            if link.href != nil && *link.href != "" {
+                linkElement := pic.CreateElement("pic:link")
+                linkElement.CreateAttr("rel", link.rel)
+                linkElement.CreateAttr("href", *link.href)
+            }
+        }
+
+        removeEmpty(element, pic)
+    }
+}
+
+// createText adds an element with text unless the text is nil or empty
+func createText(parent *etree.Element, tag string, text *string) {
+    if text != nil && *text != "" {
+        parent.CreateElement(tag).SetText(*text)
+    }
+}
+
+// createValue adds an element wrapping a value element, e.g. `ad:ad-type/ad:value`, unless the value is nil or empty
+func createValue(parent *etree.Element, tag string, valueTag string, value *string) {
+    if value != nil && *value != "" {
+        parent.CreateElement(tag).CreateElement(valueTag).SetText(*value)
+    }
+}
+
+// removeEmpty removes an element without attributes, children or text from its parent
+func removeEmpty(parent *etree.Element, element *etree.Element) {
+    if len(element.Attr) < 1 && len(element.Child) < 1 {
+        parent.RemoveChild(element)
+    }
+}
+
+// createTime adds an element with an RFC 3339 timestamp unless the time is nil
+func createTime(parent *etree.Element, tag string, t *time.Time) {
+    if t != nil {
+        parent.CreateElement(tag).SetText(t.Format(time.RFC3339Nano))
+    }
+}
@@ -0,0 +1,168 @@
+package auparser_test
+
+import (
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/internal/testutil"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+)
+
+// roundTrip encodes an advert, serialises the document and parses it again
+func roundTrip(t *testing.T, advert *models.Advert) *models.Advert {
+    doc, err := auparser.EncodeAdvert(advert, auparser.IncludeServerFields())
+    if err != nil {
+        t.Fatalf("unable to encode advert: %v", err)
+    }
+
+    data, err := doc.WriteToBytes()
+    if err != nil {
+        t.Fatalf("unable to serialise advert: %v", err)
+    }
+
+    decoded := etree.NewDocument()
+    if err := decoded.ReadFromBytes(data); err != nil {
+        t.Fatalf("unable to read encoded advert %s: %v", data, err)
+    }
+
+    parsed, report := auparser.ParseAdvert(decoded)
+    if report.IsFatal() {
+        t.Fatalf("unable to parse encoded advert %s: %v", data, report)
+    }
+
+    return parsed
+}
+
+func TestEncodeAdvertRoundTrip(t *testing.T) {
+    advert, report := auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    if report.IsFatal() {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if parsed := roundTrip(t, advert); !reflect.DeepEqual(parsed, advert) {
+        t.Errorf("expected a lossless round trip:\n%+v\n%+v", advert, parsed)
+    }
+
+    doc := testutil.ReadFixture(t, "testdata/ads.xml")
+    doc.SetRoot(doc.FindElement("//ad:ad[@id='1']").Copy())
+
+    minimal, _ := auparser.ParseAdvert(doc)
+    if parsed := roundTrip(t, minimal); !reflect.DeepEqual(parsed, minimal) {
+        t.Errorf("expected a lossless round trip of a minimal advert:\n%+v\n%+v", minimal, parsed)
+    }
+}
+
+func TestEncodeAdvertDraft(t *testing.T) {
+    title, description, priceType, currency := "Road bike", "Barely used & serviced\nPick up only", "FIXED", "AUD"
+    amount := money.New(25000, currency)
+
+    doc, err := auparser.EncodeAdvert(&models.Advert{
+        Title:       title,
+        Description: &description,
+        Price:       &models.AdvertPrice{Type: &priceType, Amount: &amount, Currency: &currency},
+        Category:    &models.AdvertCategory{ID: 18320},
+        Attributes:  []models.AdvertAttribute{{KeySlug: "bikes.type_s", Values: []models.AttributeValue{{Slug: "road"}, {Slug: "gravel"}}}},
+    })
+    if err != nil {
+        t.Fatalf("unable to encode draft: %v", err)
+    }
+
+    root := doc.Root()
+    if root.Space != "ad" || root.Tag != "ad" || root.SelectAttr("id") != nil || root.SelectAttrValue("xmlns:attr", "") == "" {
+        t.Errorf("unexpected root %s:%s %v", root.Space, root.Tag, root.Attr)
+    }
+
+    if text := doc.FindElement("//ad:description").Text(); text != "Barely used &amp; serviced<br/>Pick up only" {
+        t.Errorf("expected the plain text description to be escaped, got %q", text)
+    }
+
+    if amount := doc.FindElement("//ad:price/types:amount").Text(); amount != "250.00" {
+        t.Errorf("unexpected amount %q", amount)
+    }
+
+    if values := doc.FindElements("//attr:attribute[@name='bikes.type_s']/attr:value"); len(values) != 2 {
+        t.Errorf("expected both attribute values, got %d", len(values))
+    }
+
+    if _, err := auparser.EncodeAdvert(nil); !errors.Is(err, auparser.ErrNilAdvert) {
+        t.Errorf("expected nil advert to be rejected, got %v", err)
+    }
+}
+
+func TestEncodeAdvertPayload(t *testing.T) {
+    userID, status, priceType := uint(42), "ACTIVE", "FREE"
+    created := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+    doc, err := auparser.EncodeAdvert(&models.Advert{
+        ID:        1234,
+        Title:     "Moving boxes",
+        Status:    &status,
+        UserID:    &userID,
+        Price:     &models.AdvertPrice{Type: &priceType},
+        Category:  &models.AdvertCategory{ID: 18320},
+        Timestamp: models.AdvertTimestamp{CreationTime: &created, ModificationTime: &created},
+    })
+    if err != nil {
+        t.Fatalf("unable to encode draft: %v", err)
+    }
+
+    payload, err := doc.WriteToString()
+    if err != nil {
+        t.Fatalf("unable to serialise draft: %v", err)
+    }
+
+    expected := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<ad:ad xmlns:ad="http://www.ebayclassifiedsgroup.com/schema/ad/v1" xmlns:cat="http://www.ebayclassifiedsgroup.com/schema/category/v1" xmlns:loc="http://www.ebayclassifiedsgroup.com/schema/location/v1" xmlns:attr="http://www.ebayclassifiedsgroup.com/schema/attribute/v1" xmlns:types="http://www.ebayclassifiedsgroup.com/schema/types/v1" xmlns:pic="http://www.ebayclassifiedsgroup.com/schema/picture/v1">
+    <ad:title>Moving boxes</ad:title>
+    <ad:price>
+        <types:price-type>
+            <types:value>FREE</types:value>
+        </types:price-type>
+    </ad:price>
+    <cat:category id="18320"/>
+</ad:ad>
+`
+
+    if payload != expected {
+        t.Errorf("expected server fields to be left out of the payload:\n%s\ngot:\n%s", expected, payload)
+    }
+}
+
+func TestEncodeAdvertParsedPayload(t *testing.T) {
+    var adverts []*models.Advert
+
+    full, _ := auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    adverts = append(adverts, full)
+
+    for _, id := range []string{"1", "2"} {
+        doc := testutil.ReadFixture(t, "testdata/ads.xml")
+        doc.SetRoot(doc.FindElement("//ad:ad[@id='" + id + "']").Copy())
+
+        minimal, _ := auparser.ParseAdvert(doc)
+        adverts = append(adverts, minimal)
+    }
+
+    for _, advert := range adverts {
+        doc, err := auparser.EncodeAdvert(advert)
+        if err != nil {
+            t.Fatalf("unable to encode advert %d: %v", advert.ID, err)
+        }
+
+        payload, _ := doc.WriteToString()
+
+        for _, element := range doc.FindElements("//*") {
+            if len(element.Attr) < 1 && len(element.ChildElements()) < 1 && strings.TrimSpace(element.Text()) == "" {
+                t.Errorf("advert %d: unexpected empty element %s in payload:\n%s", advert.ID, element.FullTag(), payload)
+            }
+        }
+
+        if parent := doc.FindElement("//loc:parent-id"); parent != nil && parent.Text() == "0" {
+            t.Errorf("advert %d: unexpected zero parent ID in payload:\n%s", advert.ID, payload)
+        }
+    }
+}
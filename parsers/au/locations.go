@@ -0,0 +1,125 @@
+package auparser
+
+import (
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+)
+
+// LocationRequiredFields are the fields rejecting a location response in strict mode if missing or invalid,
+// including those of nested locations
+var LocationRequiredFields = []string{
+    "locations/location/id",
+    "locations/location/name",
+}
+
+// ParseLocations is to build every location of a raw XML response as a forest in document order,
+// leniently unless options say otherwise. Nested `loc:location` elements become sublocations,
+// whereas locations listed side by side are related through their `ParentID` by `models.NewLocationTree`.
+func ParseLocations(doc *etree.Document, opts ...parsers.Option) ([]models.Location, *parsers.ParseReport) {
+    report := parsers.NewParseReport(LocationRequiredFields, opts...)
+    reporter := report.Reporter(0)
+
+    if doc == nil {
+        reporter.Fatal("", parsers.ErrEmptyResponse)
+        return nil, report
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "loc" {
+        reporter.Fatal("locations", parsers.ErrUnexpectedResponse)
+        return nil, report
+    }
+
+    var rootLocations []*etree.Element
+
+    switch root.Tag {
+    case "locations": // contains multiple top-level locations
+        rootLocations = root.SelectElements("location")
+    case "location": // contains a single location
+        rootLocations = []*etree.Element{ root }
+    default:
+        reporter.Fatal("locations", parsers.ErrUnexpectedResponse)
+        return nil, report
+    }
+
+    locations := buildLocationForest(rootLocations, "locations", reporter)
+
+    if report.IsFatal() {
+        return nil, report
+    }
+
+    if len(locations) < 1 {
+        reporter.Fatal("locations/location", fmt.Errorf("no location could be built"))
+        return nil, report
+    }
+
+    return locations, report
+}
+
+// buildLocationForest builds sibling locations in document order, leaving out malformed ones
+func buildLocationForest(elements []*etree.Element, path string, reporter parsers.Reporter) []models.Location {
+    var locations []models.Location
+
+    for i, element := range elements {
+        if built := buildLocation(element, fmt.Sprintf("%s/location[%d]", path, i), reporter); built != nil {
+            locations = append(locations, *built)
+        }
+    }
+
+    return locations
+}
+
+func buildLocation(location *etree.Element, path string, reporter parsers.Reporter) *models.Location {
+    if location == nil {
+        return nil
+    }
+
+    locID, err := u.ConvString2Uint(u.ExtractAttrByTag(location, "id"))
+    if err != nil {
+        reporter.Report(path + "/id", err)
+        return nil
+    }
+
+    locName := u.FallbackStringWithReport(
+        u.ExtractText(location, "./loc:localized-name"))(
+        "", reporter, fmt.Sprintf("locations/location/%d/name", locID))
+
+    locSlug, _ := u.ExtractText(location, "./loc:id-name")
+
+    var locParentID *uint
+    if parentID, err := u.ExtractTextAsUint(location, "./loc:parent-id"); err == nil {
+        locParentID = &parentID
+    }
+
+    locChildrenCount, _ := u.ExtractTextAsUint(location, "./loc:children-count")
+
+    var coordinate *models.AdvertCoordinate
+
+    longitude, longerr := u.ExtractTextAsFloat64(location, "./loc:longitude")
+    latitude, laterr := u.ExtractTextAsFloat64(location, "./loc:latitude")
+
+    if longerr == nil && laterr == nil {
+        coordinate = &models.AdvertCoordinate{
+            Longitude: longitude,
+            Latitude: latitude,
+        }
+    }
+
+    // recursively add sublocations
+    sublocations := buildLocationForest(
+        location.FindElements("./loc:location"), fmt.Sprintf("locations/location/%d", locID), reporter)
+
+    return &models.Location{
+        ID:            locID,
+        Name:          locName,
+        Slug:          u.ReplaceStringWithNil(&locSlug, ""),
+        ParentID:      locParentID,
+        ChildrenCount: locChildrenCount,
+        Coordinate:    coordinate,
+        Sublocations:  sublocations,
+    }
+}
@@ -1,6 +1,9 @@
 package aumodels
 
-import "time"
+import (
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "time"
+)
 
 // Advert is the root element of an ad
 type Advert struct {
@@ -16,6 +19,8 @@ type Advert struct {
     Title                   string              `json:"title"`
     DescriptionExcerptB64   *string             `json:"desc_excerpt_plain_b64,omitempty"`
     DescriptionExcerptHTML  *string             `json:"desc_excerpt_html,omitempty"`
+    Description             *string             `json:"description,omitempty"`
+    DescriptionHTML         *string             `json:"description_html,omitempty"`
     Pictures                []AdvertPicture     `json:"pictures,omitempty"`
     Attributes              []AdvertAttribute   `json:"attributes,omitempty"`
     Timestamp               AdvertTimestamp     `json:"timestamp"`
@@ -24,12 +29,25 @@ type Advert struct {
 // AdvertPrice is the listing price shown on an ad
 type AdvertPrice struct {
     Type                    *string             `json:"type"`
-    Amount                  *uint               `json:"amount"`
-    HighestAmount           *uint               `json:"highest_amount,omitempty"`
+    Amount                  *money.Amount       `json:"amount"`
+    HighestAmount           *money.Amount       `json:"highest_amount,omitempty"`
     Currency                *string             `json:"currency,omitempty"`
     CurrencySymbol          *string             `json:"currency_symbol,omitempty"`
 }
 
+// Format returns the amount for display with the currency symbol, e.g. `$19.99`, or an empty string without amount
+func (price AdvertPrice) Format() string {
+    if price.Amount == nil {
+        return ""
+    }
+
+    if price.CurrencySymbol != nil {
+        return price.Amount.Format(*price.CurrencySymbol)
+    }
+
+    return price.Amount.String()
+}
+
 // AdvertPosition is the positional information of an ad
 type AdvertPosition struct {
     Address                 *string             `json:"address,omitempty"`
@@ -62,13 +80,15 @@ type AdvertCategory struct {
     ChildrenCount           *uint               `json:"children_count"`
 }
 
-// AdvertAttribute is the attribute associated with an ad
+// AdvertAttribute is the attribute associated with an ad.
+// `ValueSlug` and `ValueName` hold the first value, whereas `Values` holds all values of multi-valued attributes.
 type AdvertAttribute struct {
     KeySlug                 string              `json:"key_slug"`
     KeyName                 string              `json:"key_name"`
     ValueType               *string             `json:"value_type"`
     ValueSlug               *string             `json:"value_slug"`
     ValueName               *string             `json:"value_name"`
+    Values                  []AttributeValue    `json:"values,omitempty"`
 }
 
 // AdvertPicture is the picture associated with an ad
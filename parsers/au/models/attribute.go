@@ -0,0 +1,391 @@
+package aumodels
+
+import (
+    "errors"
+    "fmt"
+    "math/big"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Value types of attributes
+const (
+    AttributeTypeString  = "STRING"
+    AttributeTypeEnum    = "ENUM"
+    AttributeTypeInteger = "INTEGER"
+    AttributeTypeDecimal = "DECIMAL"
+    AttributeTypeDate    = "DATE"
+    AttributeTypeBoolean = "BOOLEAN"
+)
+
+// Errors of attributes that do not conform to the schema of their category
+var (
+    ErrAttributeUnknown  = errors.New("attribute not supported by the category")
+    ErrAttributeRequired = errors.New("required attribute missing")
+    ErrAttributeValue    = errors.New("attribute value not allowed")
+)
+
+// Errors of typed attribute values
+var (
+    ErrAttributeType    = errors.New("attribute is of another type")
+    ErrAttributeNoValue = errors.New("attribute has no value")
+)
+
+// AttributeSchema is the attribute metadata of a category, describing the attributes its ads may carry
+type AttributeSchema struct {
+    CategoryID              uint                    `json:"category_id"`
+    Attributes              []AttributeDefinition   `json:"attributes"`
+}
+
+// AttributeDefinition describes an attribute supported by a category
+type AttributeDefinition struct {
+    Name                    string              `json:"name"`
+    Label                   string              `json:"label"`
+    Type                    string              `json:"type"`
+    Unit                    *string             `json:"unit,omitempty"`
+    Required                bool                `json:"required"`
+    Searchable              bool                `json:"searchable"`
+    Values                  []AttributeValue    `json:"values,omitempty"`
+}
+
+// AttributeValue is an allowed value of an enumerated attribute
+type AttributeValue struct {
+    Slug                    string              `json:"slug"`
+    Label                   string              `json:"label"`
+}
+
+// AttributeError is an attribute of an ad that does not conform to the schema of its category
+type AttributeError struct {
+    Name  string // Name of the attribute, e.g. `cars.carmake_s`
+    Value string // Offending value, empty if the attribute is missing
+    Err   error  // `ErrAttributeUnknown`, `ErrAttributeRequired` or `ErrAttributeValue`
+}
+
+func (e *AttributeError) Error() string {
+    if e.Value == "" {
+        return fmt.Sprintf("%s: %v", e.Name, e.Err)
+    }
+
+    return fmt.Sprintf("%s: %v: %q", e.Name, e.Err, e.Value)
+}
+
+// Unwrap returns the reason of the failure
+func (e *AttributeError) Unwrap() error {
+    return e.Err
+}
+
+// ValidationError lists every attribute of an ad that does not conform to the schema of its category
+type ValidationError struct {
+    CategoryID uint              // Category whose schema was violated
+    Errors     []*AttributeError // Offending attributes in schema order, followed by unknown ones once per name
+}
+
+func (e *ValidationError) Error() string {
+    messages := make([]string, len(e.Errors))
+    for i, err := range e.Errors {
+        messages[i] = err.Error()
+    }
+
+    return fmt.Sprintf("invalid attributes for category %d: %s", e.CategoryID, strings.Join(messages, "; "))
+}
+
+// Is matches the reason of any of the offending attributes
+func (e *ValidationError) Is(target error) bool {
+    for _, err := range e.Errors {
+        if errors.Is(err, target) {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Definition returns the definition of the attribute with a name
+func (schema *AttributeSchema) Definition(name string) (*AttributeDefinition, bool) {
+    for i := range schema.Attributes {
+        if schema.Attributes[i].Name == name {
+            return &schema.Attributes[i], true
+        }
+    }
+
+    return nil, false
+}
+
+// Searchable returns the definitions of the attributes that ads can be searched by
+func (schema *AttributeSchema) Searchable() []*AttributeDefinition {
+    var searchable []*AttributeDefinition
+
+    for i := range schema.Attributes {
+        if schema.Attributes[i].Searchable {
+            searchable = append(searchable, &schema.Attributes[i])
+        }
+    }
+
+    return searchable
+}
+
+// Validate checks the attributes of an ad against the schema, returning a `*ValidationError`
+// if a required attribute is missing, an attribute is not supported or a value does not match its type
+func (schema *AttributeSchema) Validate(attributes []AdvertAttribute) error {
+    var errs []*AttributeError
+
+    values := make(map[string][]string)
+    for _, attribute := range attributes {
+        for _, value := range attribute.allValues() {
+            if value.Slug != "" {
+                values[attribute.KeySlug] = append(values[attribute.KeySlug], value.Slug)
+            }
+        }
+    }
+
+    for i := range schema.Attributes {
+        definition := &schema.Attributes[i]
+        given := values[definition.Name]
+
+        if len(given) < 1 {
+            if definition.Required {
+                errs = append(errs, &AttributeError{Name: definition.Name, Err: ErrAttributeRequired})
+            }
+
+            continue
+        }
+
+        for _, value := range given {
+            if !definition.Allows(value) {
+                errs = append(errs, &AttributeError{Name: definition.Name, Value: value, Err: ErrAttributeValue})
+            }
+        }
+    }
+
+    unknown := make(map[string]bool)
+    for _, attribute := range attributes {
+        if _, ok := schema.Definition(attribute.KeySlug); !ok && !unknown[attribute.KeySlug] {
+            unknown[attribute.KeySlug] = true
+            errs = append(errs, &AttributeError{Name: attribute.KeySlug, Err: ErrAttributeUnknown})
+        }
+    }
+
+    if len(errs) > 0 {
+        return &ValidationError{CategoryID: schema.CategoryID, Errors: errs}
+    }
+
+    return nil
+}
+
+// Allows reports whether a value is valid for the attribute, i.e. one of the allowed values of an enumeration
+// or a value of its type. Values of unknown types and of enumerations without allowed values are always allowed.
+func (definition *AttributeDefinition) Allows(value string) bool {
+    switch strings.ToUpper(definition.Type) {
+    case AttributeTypeEnum:
+        if len(definition.Values) < 1 { // the metadata does not list the values
+            return true
+        }
+
+        for _, allowed := range definition.Values {
+            if allowed.Slug == value {
+                return true
+            }
+        }
+
+        return false
+    default:
+        _, err := ConvertAttributeValue(definition.Type, value)
+        return err == nil
+    }
+}
+
+// ValueLabel returns the localised label of an allowed value, or the value itself if it has none
+func (definition *AttributeDefinition) ValueLabel(value string) string {
+    for _, allowed := range definition.Values {
+        if allowed.Slug == value && allowed.Label != "" {
+            return allowed.Label
+        }
+    }
+
+    return value
+}
+
+// ConvertAttributeValue converts the text of a value to the Go type of an attribute type:
+// `int64` for INTEGER, `Decimal` for DECIMAL, `bool` for BOOLEAN, `time.Time` for DATE and `string` otherwise.
+// Enumerated values remain their slug, see `AdvertAttribute.Enum` for their label.
+func ConvertAttributeValue(valueType string, value string) (interface{}, error) {
+    switch strings.ToUpper(valueType) {
+    case AttributeTypeInteger:
+        return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+    case AttributeTypeDecimal:
+        return ParseDecimal(value)
+    case AttributeTypeBoolean:
+        return strconv.ParseBool(strings.TrimSpace(value))
+    case AttributeTypeDate:
+        return ParseAttributeDate(value)
+    default:
+        return value, nil
+    }
+}
+
+// Decimal is the exact text of a decimal value, e.g. `2.50`, as validated by `ParseDecimal`
+type Decimal string
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// ParseDecimal reads the value of a decimal attribute such as `-12.5`, failing with a `*strconv.NumError`
+// for anything but digits with an optional sign and decimal point
+func ParseDecimal(value string) (Decimal, error) {
+    value = strings.TrimSpace(value)
+
+    if !decimalPattern.MatchString(value) {
+        return "", &strconv.NumError{Func: "ParseDecimal", Num: value, Err: strconv.ErrSyntax}
+    }
+
+    return Decimal(value), nil
+}
+
+// Rat returns the exact value of the decimal, or nil if it was not validated by `ParseDecimal`
+func (d Decimal) Rat() *big.Rat {
+    if !decimalPattern.MatchString(string(d)) {
+        return nil
+    }
+
+    r, _ := new(big.Rat).SetString(string(d))
+    return r
+}
+
+// Float64 returns the nearest floating-point number of the decimal, which may lose precision
+func (d Decimal) Float64() (float64, error) {
+    return strconv.ParseFloat(string(d), 64)
+}
+
+func (d Decimal) String() string {
+    return string(d)
+}
+
+// ParseAttributeDate reads the value of a date attribute, either a date such as `2019-04-01`
+// or a timestamp such as `2019-04-01T08:15:30.000Z`
+func ParseAttributeDate(value string) (time.Time, error) {
+    value = strings.TrimSpace(value)
+
+    if t, err := time.Parse("2006-01-02", value); err == nil {
+        return t, nil
+    }
+
+    return time.Parse(time.RFC3339, value)
+}
+
+// Type returns the upper-case value type of the attribute, STRING if it has none
+func (attribute AdvertAttribute) Type() string {
+    if attribute.ValueType == nil || *attribute.ValueType == "" {
+        return AttributeTypeString
+    }
+
+    return strings.ToUpper(*attribute.ValueType)
+}
+
+// allValues returns the values of the attribute, falling back to `ValueSlug` and `ValueName`
+// for attributes built without `Values`
+func (attribute AdvertAttribute) allValues() []AttributeValue {
+    if len(attribute.Values) > 0 {
+        return attribute.Values
+    }
+
+    if attribute.ValueSlug == nil {
+        return nil
+    }
+
+    value := AttributeValue{Slug: *attribute.ValueSlug}
+    if attribute.ValueName != nil {
+        value.Label = *attribute.ValueName
+    }
+
+    return []AttributeValue{value}
+}
+
+// Value returns the first value converted according to the value type, see `ConvertAttributeValue`
+func (attribute AdvertAttribute) Value() (interface{}, error) {
+    values := attribute.allValues()
+    if len(values) < 1 {
+        return nil, ErrAttributeNoValue
+    }
+
+    return ConvertAttributeValue(attribute.Type(), values[0].Slug)
+}
+
+// TypedValues returns all values converted according to the value type, see `ConvertAttributeValue`
+func (attribute AdvertAttribute) TypedValues() ([]interface{}, error) {
+    var typed []interface{}
+
+    for _, value := range attribute.allValues() {
+        converted, err := ConvertAttributeValue(attribute.Type(), value.Slug)
+        if err != nil {
+            return nil, err
+        }
+
+        typed = append(typed, converted)
+    }
+
+    return typed, nil
+}
+
+// typedValue returns the first value converted to a type, failing with `ErrAttributeType` if the attribute is of another type
+func (attribute AdvertAttribute) typedValue(valueType string) (interface{}, error) {
+    if attribute.Type() != valueType {
+        return nil, fmt.Errorf("%w: %s is %s, not %s", ErrAttributeType, attribute.KeySlug, attribute.Type(), valueType)
+    }
+
+    return attribute.Value()
+}
+
+// Int returns the first value of an INTEGER attribute, e.g. the year of a car
+func (attribute AdvertAttribute) Int() (int64, error) {
+    value, err := attribute.typedValue(AttributeTypeInteger)
+    if err != nil {
+        return 0, err
+    }
+
+    return value.(int64), nil
+}
+
+// Decimal returns the first value of a DECIMAL attribute exactly, e.g. an engine size of `1.8`
+func (attribute AdvertAttribute) Decimal() (Decimal, error) {
+    value, err := attribute.typedValue(AttributeTypeDecimal)
+    if err != nil {
+        return "", err
+    }
+
+    return value.(Decimal), nil
+}
+
+// Bool returns the first value of a BOOLEAN attribute
+func (attribute AdvertAttribute) Bool() (bool, error) {
+    value, err := attribute.typedValue(AttributeTypeBoolean)
+    if err != nil {
+        return false, err
+    }
+
+    return value.(bool), nil
+}
+
+// Time returns the first value of a DATE attribute
+func (attribute AdvertAttribute) Time() (time.Time, error) {
+    value, err := attribute.typedValue(AttributeTypeDate)
+    if err != nil {
+        return time.Time{}, err
+    }
+
+    return value.(time.Time), nil
+}
+
+// Enum returns the first value of an ENUM attribute along with its localised label
+func (attribute AdvertAttribute) Enum() (AttributeValue, error) {
+    if attribute.Type() != AttributeTypeEnum {
+        return AttributeValue{}, fmt.Errorf("%w: %s is %s, not %s", ErrAttributeType, attribute.KeySlug, attribute.Type(), AttributeTypeEnum)
+    }
+
+    values := attribute.allValues()
+    if len(values) < 1 {
+        return AttributeValue{}, ErrAttributeNoValue
+    }
+
+    return values[0], nil
+}
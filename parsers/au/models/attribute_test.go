@@ -0,0 +1,107 @@
+package aumodels_test
+
+import (
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "math/big"
+    "strconv"
+    "testing"
+)
+
+func stringPtr(v string) *string {
+    return &v
+}
+
+func TestAttributeSchemaValidate(t *testing.T) {
+    schema := aumodels.AttributeSchema{CategoryID: 18320, Attributes: []aumodels.AttributeDefinition{
+        {Name: "cars.carmake_s", Type: aumodels.AttributeTypeEnum, Required: true, Values: []aumodels.AttributeValue{{Slug: "toyota"}}},
+        {Name: "cars.caryear_i", Type: aumodels.AttributeTypeInteger},
+        {Name: "cars.registered_b", Type: aumodels.AttributeTypeBoolean},
+        {Name: "cars.regoexpiry_d", Type: aumodels.AttributeTypeDate},
+    }}
+
+    valid := []aumodels.AdvertAttribute{
+        {KeySlug: "cars.carmake_s", ValueSlug: stringPtr("toyota")},
+        {KeySlug: "cars.caryear_i", ValueSlug: stringPtr("2015")},
+        {KeySlug: "cars.registered_b", ValueSlug: stringPtr("true")},
+        {KeySlug: "cars.regoexpiry_d", ValueSlug: stringPtr("2020-03-31")},
+    }
+
+    if err := schema.Validate(valid); err != nil {
+        t.Errorf("unexpected error %v", err)
+    }
+
+    err := schema.Validate([]aumodels.AdvertAttribute{
+        {KeySlug: "cars.caryear_i", ValueSlug: stringPtr("twenty fifteen")},
+        {KeySlug: "cars.colour_s", ValueSlug: stringPtr("red")},
+    })
+
+    var validation *aumodels.ValidationError
+    if !errors.As(err, &validation) || len(validation.Errors) != 3 {
+        t.Fatalf("expected three offending attributes, got %v", err)
+    }
+
+    if e := validation.Errors[0]; e.Name != "cars.carmake_s" || !errors.Is(e, aumodels.ErrAttributeRequired) {
+        t.Errorf("expected the make to be required, got %v", e)
+    }
+
+    if e := validation.Errors[1]; e.Value != "twenty fifteen" || !errors.Is(e, aumodels.ErrAttributeValue) {
+        t.Errorf("expected the year to be rejected, got %v", e)
+    }
+
+    if !errors.Is(err, aumodels.ErrAttributeUnknown) {
+        t.Errorf("expected the colour to be unknown, got %v", err)
+    }
+}
+
+func TestAttributeSchemaValidateUnknown(t *testing.T) {
+    schema := aumodels.AttributeSchema{CategoryID: 18320, Attributes: []aumodels.AttributeDefinition{
+        {Name: "cars.carmake_s", Type: aumodels.AttributeTypeEnum},
+    }}
+
+    err := schema.Validate([]aumodels.AdvertAttribute{
+        {KeySlug: "cars.carmake_s", ValueSlug: stringPtr("lada")},
+        {KeySlug: "cars.colour_s", ValueSlug: stringPtr("red")},
+        {KeySlug: "cars.colour_s", ValueSlug: stringPtr("blue")},
+    })
+
+    var validation *aumodels.ValidationError
+    if !errors.As(err, &validation) || len(validation.Errors) != 1 || validation.Errors[0].Name != "cars.colour_s" {
+        t.Fatalf("expected the colour to be reported once as unknown, got %v", err)
+    }
+}
+
+func TestAttributeDefinitionAllows(t *testing.T) {
+    open := aumodels.AttributeDefinition{Name: "cars.carmake_s", Type: aumodels.AttributeTypeEnum}
+    if !open.Allows("lada") {
+        t.Errorf("expected an enumeration without allowed values to allow any value")
+    }
+
+    closed := aumodels.AttributeDefinition{Name: "cars.carmake_s", Type: aumodels.AttributeTypeEnum, Values: []aumodels.AttributeValue{{Slug: "toyota"}}}
+    if closed.Allows("lada") || !closed.Allows("toyota") {
+        t.Errorf("expected an enumeration to allow only its values")
+    }
+}
+
+func TestAdvertAttributeDecimal(t *testing.T) {
+    attribute := aumodels.AdvertAttribute{KeySlug: "cars.enginesize_d", ValueType: stringPtr("DECIMAL"), ValueSlug: stringPtr(" 0.10 ")}
+
+    decimal, err := attribute.Decimal()
+    if err != nil || decimal != "0.10" {
+        t.Fatalf("expected the exact text of the decimal, got %q: %v", decimal, err)
+    }
+
+    if rat := decimal.Rat(); rat == nil || rat.Cmp(big.NewRat(1, 10)) != 0 {
+        t.Errorf("expected exactly one tenth, got %v", rat)
+    }
+
+    for _, invalid := range []string{"1e5", "1/3", "0x10", "1,5", ".", ""} {
+        if _, err := aumodels.ParseDecimal(invalid); !errors.Is(err, strconv.ErrSyntax) {
+            t.Errorf("expected %q to be rejected, got %v", invalid, err)
+        }
+    }
+
+    if _, err := attribute.Int(); !errors.Is(err, aumodels.ErrAttributeType) {
+        t.Errorf("expected the decimal not to be an integer, got %v", err)
+    }
+}
@@ -11,3 +11,8 @@ type Categories struct {
     Subcategories           []Categories        `json:"subcategories,omitempty"`
     IsRootCategory          bool                `json:"is_root"`
 }
+
+// IsLeaf reports whether the category has no subcategories according to its `ChildrenCount`
+func (category Categories) IsLeaf() bool {
+    return category.ChildrenCount == 0
+}
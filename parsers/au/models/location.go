@@ -0,0 +1,196 @@
+package aumodels
+
+import "strings"
+
+// Location is an entry of the location hierarchy, i.e. a state, region or suburb
+type Location struct {
+    ID                      uint                `json:"id"`
+    Name                    string              `json:"name"`
+    Slug                    *string             `json:"slug,omitempty"`
+    ParentID                *uint               `json:"parent_id"`
+    ChildrenCount           uint                `json:"children_count"`
+    Coordinate              *AdvertCoordinate   `json:"coordinate,omitempty"`
+    Sublocations            []Location          `json:"sublocations,omitempty"`
+}
+
+// LocationLevel is the level of a location in the hierarchy
+type LocationLevel int
+
+// Levels of the location hierarchy
+const (
+    LocationLevelCountry LocationLevel = iota // the entire marketplace, with ID zero
+    LocationLevelState                        // state or territory
+    LocationLevelRegion                       // region within a state
+    LocationLevelSuburb                       // suburb within a region
+)
+
+// LocationTree indexes the locations returned by `ParseLocations` for lookups and traversal.
+// Locations are related by nesting, or by `ParentID` if the response lists them side by side.
+// The tree refers to the locations it was built from, which must not be modified afterwards.
+type LocationTree struct {
+    roots    []*Location
+    byID     map[uint]*Location
+    byName   map[string][]*Location
+    parent   map[*Location]*Location
+    children map[*Location][]*Location
+}
+
+// NewLocationTree builds a tree from a location forest. If an ID occurs more than once,
+// lookups return the first location in document order.
+func NewLocationTree(forest []Location) *LocationTree {
+    tree := &LocationTree{
+        byID:     make(map[uint]*Location),
+        byName:   make(map[string][]*Location),
+        parent:   make(map[*Location]*Location),
+        children: make(map[*Location][]*Location),
+    }
+
+    var all []*Location
+
+    var index func(location *Location, parent *Location)
+    index = func(location *Location, parent *Location) {
+        if _, exists := tree.byID[location.ID]; !exists {
+            tree.byID[location.ID] = location
+        }
+
+        key := strings.ToLower(strings.TrimSpace(location.Name))
+        tree.byName[key] = append(tree.byName[key], location)
+
+        if parent != nil {
+            tree.link(location, parent)
+        }
+
+        all = append(all, location)
+
+        for i := range location.Sublocations {
+            index(&location.Sublocations[i], location)
+        }
+    }
+
+    for i := range forest {
+        index(&forest[i], nil)
+    }
+
+    for _, location := range all { // relate locations listed side by side
+        if _, nested := tree.parent[location]; nested || location.ParentID == nil || *location.ParentID == location.ID {
+            continue
+        }
+
+        if parent, ok := tree.byID[*location.ParentID]; ok && !tree.isAncestor(location, parent) {
+            tree.link(location, parent)
+        }
+    }
+
+    for _, location := range all {
+        if _, ok := tree.parent[location]; !ok {
+            tree.roots = append(tree.roots, location)
+        }
+    }
+
+    return tree
+}
+
+func (tree *LocationTree) link(location *Location, parent *Location) {
+    tree.parent[location] = parent
+    tree.children[parent] = append(tree.children[parent], location)
+}
+
+// isAncestor reports whether a location is the given location or one of its ancestors, which guards against cycles
+func (tree *LocationTree) isAncestor(location *Location, of *Location) bool {
+    for ; of != nil; of = tree.parent[of] {
+        if of == location {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Len returns the number of distinct location IDs in the tree
+func (tree *LocationTree) Len() int {
+    return len(tree.byID)
+}
+
+// Roots returns the top-level locations
+func (tree *LocationTree) Roots() []*Location {
+    return tree.roots
+}
+
+// ByID returns the location with an ID
+func (tree *LocationTree) ByID(id uint) (*Location, bool) {
+    location, ok := tree.byID[id]
+    return location, ok
+}
+
+// ByName returns all locations with a name, ignoring case, as suburbs in different states may share a name
+func (tree *LocationTree) ByName(name string) []*Location {
+    return tree.byName[strings.ToLower(strings.TrimSpace(name))]
+}
+
+// Parent returns the location containing the location with an ID, or false for roots and unknown IDs
+func (tree *LocationTree) Parent(id uint) (*Location, bool) {
+    parent, ok := tree.parent[tree.byID[id]]
+    return parent, ok
+}
+
+// Children returns the locations directly within the location with an ID
+func (tree *LocationTree) Children(id uint) []*Location {
+    return tree.children[tree.byID[id]]
+}
+
+// Ancestors returns the locations containing the location with an ID, nearest first
+func (tree *LocationTree) Ancestors(id uint) []*Location {
+    var ancestors []*Location
+
+    for parent := tree.parent[tree.byID[id]]; parent != nil; parent = tree.parent[parent] {
+        ancestors = append(ancestors, parent)
+    }
+
+    return ancestors
+}
+
+// Path returns the locations from the root to the location with an ID, both included, or nil for unknown IDs
+func (tree *LocationTree) Path(id uint) []*Location {
+    location, ok := tree.byID[id]
+    if !ok {
+        return nil
+    }
+
+    ancestors := tree.Ancestors(id)
+    path := make([]*Location, 0, len(ancestors) + 1)
+
+    for i := len(ancestors) - 1; i >= 0; i-- {
+        path = append(path, ancestors[i])
+    }
+
+    return append(path, location)
+}
+
+// Level returns the level of the location with an ID. Levels count from the country if the tree is rooted at ID zero,
+// and from the states otherwise. Unknown IDs are at the country level.
+func (tree *LocationTree) Level(id uint) LocationLevel {
+    path := tree.Path(id)
+    if len(path) < 1 {
+        return LocationLevelCountry
+    }
+
+    if path[0].ID == 0 {
+        return LocationLevel(len(path) - 1)
+    }
+
+    return LocationLevel(len(path))
+}
+
+// Resolve returns the full path of the most specific location among the locations of an ad,
+// which usually lists a suburb along with some of its parents, or nil if none of them is known
+func (tree *LocationTree) Resolve(locations []AdvertLocation) []*Location {
+    var deepest []*Location
+
+    for _, location := range locations {
+        if path := tree.Path(location.ID); len(path) > len(deepest) {
+            deepest = path
+        }
+    }
+
+    return deepest
+}
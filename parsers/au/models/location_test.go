@@ -0,0 +1,71 @@
+package aumodels_test
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "testing"
+)
+
+func uintPtr(v uint) *uint {
+    return &v
+}
+
+func newLocationTree() *aumodels.LocationTree {
+    return aumodels.NewLocationTree([]aumodels.Location{
+        {ID: 3008839, Name: "New South Wales", Sublocations: []aumodels.Location{
+            {ID: 3003435, Name: "Sydney", ParentID: uintPtr(3008839), Sublocations: []aumodels.Location{
+                {ID: 3003602, Name: "Sydney City", ParentID: uintPtr(3003435)},
+            }},
+        }},
+        {ID: 3008844, Name: "Victoria", Sublocations: []aumodels.Location{
+            {ID: 3001317, Name: "Ballarat", ParentID: uintPtr(3008844)},
+        }},
+        {ID: 3003714, Name: "Newtown", ParentID: uintPtr(3003435)},
+        {ID: 3001400, Name: "Newtown", ParentID: uintPtr(3001317)},
+        {ID: 42, Name: "Orphan", ParentID: uintPtr(7)},
+    })
+}
+
+func TestLocationTreeLookup(t *testing.T) {
+    tree := newLocationTree()
+
+    if tree.Len() != 8 || len(tree.Roots()) != 3 {
+        t.Errorf("unexpected size %d with %d roots", tree.Len(), len(tree.Roots()))
+    }
+
+    if newtowns := tree.ByName(" newtown"); len(newtowns) != 2 || newtowns[0].ID != 3003714 || newtowns[1].ID != 3001400 {
+        t.Errorf("expected both suburbs named Newtown, got %+v", newtowns)
+    }
+
+    if parent, ok := tree.Parent(3003714); !ok || parent.ID != 3003435 {
+        t.Errorf("expected the parent ID to relate side-by-side locations, got %+v", parent)
+    }
+
+    if children := tree.Children(3003435); len(children) != 2 || children[0].ID != 3003602 || children[1].ID != 3003714 {
+        t.Errorf("unexpected children %+v", children)
+    }
+
+    if ancestors := tree.Ancestors(3001400); len(ancestors) != 2 || ancestors[0].ID != 3001317 || ancestors[1].ID != 3008844 {
+        t.Errorf("unexpected ancestors %+v", ancestors)
+    }
+
+    if level := tree.Level(3003714); level != aumodels.LocationLevelSuburb {
+        t.Errorf("expected a suburb, got level %d", level)
+    }
+
+    if level := tree.Level(3008844); level != aumodels.LocationLevelState {
+        t.Errorf("expected a state, got level %d", level)
+    }
+}
+
+func TestLocationTreeResolve(t *testing.T) {
+    tree := newLocationTree()
+
+    path := tree.Resolve([]aumodels.AdvertLocation{{ID: 3008839}, {ID: 3003602}, {ID: 3003435}})
+    if len(path) != 3 || path[0].ID != 3008839 || path[1].ID != 3003435 || path[2].ID != 3003602 {
+        t.Errorf("expected the path to the suburb, got %+v", path)
+    }
+
+    if path := tree.Resolve([]aumodels.AdvertLocation{{ID: 1}}); path != nil {
+        t.Errorf("expected unknown locations not to resolve, got %+v", path)
+    }
+}
@@ -0,0 +1,205 @@
+package aumodels
+
+import "strings"
+
+// CategoryTree indexes the categories returned by `ParseCategories` for lookups and traversal.
+// The tree refers to the categories it was built from, which must not be modified afterwards.
+type CategoryTree struct {
+    roots  []*Categories
+    byID   map[uint]*Categories
+    bySlug map[string]*Categories
+    parent map[*Categories]*Categories
+}
+
+// FlatCategory is a category along with its depth in the tree, the roots being at depth zero
+type FlatCategory struct {
+    *Categories
+    Depth                   int                 `json:"depth"`
+}
+
+// NewCategoryTree builds a tree from a category forest. If an ID or slug occurs more than once,
+// lookups return the first category in document order.
+func NewCategoryTree(forest []Categories) *CategoryTree {
+    tree := &CategoryTree{
+        byID:   make(map[uint]*Categories),
+        bySlug: make(map[string]*Categories),
+        parent: make(map[*Categories]*Categories),
+    }
+
+    var index func(category *Categories, parent *Categories)
+    index = func(category *Categories, parent *Categories) {
+        if _, exists := tree.byID[category.ID]; !exists {
+            tree.byID[category.ID] = category
+        }
+
+        if _, exists := tree.bySlug[category.Slug]; !exists && category.Slug != "" {
+            tree.bySlug[category.Slug] = category
+        }
+
+        if parent != nil {
+            tree.parent[category] = parent
+        }
+
+        for i := range category.Subcategories {
+            index(&category.Subcategories[i], category)
+        }
+    }
+
+    for i := range forest {
+        tree.roots = append(tree.roots, &forest[i])
+        index(&forest[i], nil)
+    }
+
+    return tree
+}
+
+// Len returns the number of distinct category IDs in the tree
+func (tree *CategoryTree) Len() int {
+    return len(tree.byID)
+}
+
+// Roots returns the top-level categories
+func (tree *CategoryTree) Roots() []*Categories {
+    return tree.roots
+}
+
+// ByID returns the category with an ID
+func (tree *CategoryTree) ByID(id uint) (*Categories, bool) {
+    category, ok := tree.byID[id]
+    return category, ok
+}
+
+// BySlug returns the category with a slug, e.g. `cars-vans-utes`
+func (tree *CategoryTree) BySlug(slug string) (*Categories, bool) {
+    category, ok := tree.bySlug[slug]
+    return category, ok
+}
+
+// Parent returns the category containing the category with an ID, or false for roots and unknown IDs
+func (tree *CategoryTree) Parent(id uint) (*Categories, bool) {
+    parent, ok := tree.parent[tree.byID[id]]
+    return parent, ok
+}
+
+// Ancestors returns the categories containing the category with an ID, nearest first
+func (tree *CategoryTree) Ancestors(id uint) []*Categories {
+    var ancestors []*Categories
+
+    for parent := tree.parent[tree.byID[id]]; parent != nil; parent = tree.parent[parent] {
+        ancestors = append(ancestors, parent)
+    }
+
+    return ancestors
+}
+
+// Breadcrumb returns the path from the root to the category with an ID, both included, or nil for unknown IDs
+func (tree *CategoryTree) Breadcrumb(id uint) []*Categories {
+    category, ok := tree.byID[id]
+    if !ok {
+        return nil
+    }
+
+    ancestors := tree.Ancestors(id)
+    breadcrumb := make([]*Categories, 0, len(ancestors) + 1)
+
+    for i := len(ancestors) - 1; i >= 0; i-- {
+        breadcrumb = append(breadcrumb, ancestors[i])
+    }
+
+    return append(breadcrumb, category)
+}
+
+// Path returns the names along the breadcrumb of a category joined by a separator, e.g. `Cars & Vehicles > Cars, Vans & Utes`
+func (tree *CategoryTree) Path(id uint, separator string) string {
+    breadcrumb := tree.Breadcrumb(id)
+    names := make([]string, len(breadcrumb))
+
+    for i, category := range breadcrumb {
+        names[i] = category.Name
+    }
+
+    return strings.Join(names, separator)
+}
+
+// Depth returns the number of ancestors of the category with an ID, or -1 for unknown IDs
+func (tree *CategoryTree) Depth(id uint) int {
+    if _, ok := tree.byID[id]; !ok {
+        return -1
+    }
+
+    return len(tree.Ancestors(id))
+}
+
+// Children returns the direct subcategories of the category with an ID
+func (tree *CategoryTree) Children(id uint) []*Categories {
+    var children []*Categories
+
+    if category, ok := tree.byID[id]; ok {
+        for i := range category.Subcategories {
+            children = append(children, &category.Subcategories[i])
+        }
+    }
+
+    return children
+}
+
+// Descendants returns all subcategories below the category with an ID in depth-first order
+func (tree *CategoryTree) Descendants(id uint) []*Categories {
+    var descendants []*Categories
+
+    if category, ok := tree.byID[id]; ok {
+        for _, flat := range flatten(category.Subcategories, 0, -1) {
+            descendants = append(descendants, flat.Categories)
+        }
+    }
+
+    return descendants
+}
+
+// IsLeaf reports whether the category with an ID has no subcategories according to its `ChildrenCount`,
+// which holds even if the subcategories were not part of the response
+func (tree *CategoryTree) IsLeaf(id uint) bool {
+    category, ok := tree.byID[id]
+    return ok && category.IsLeaf()
+}
+
+// Leaves returns all leaf categories in depth-first order
+func (tree *CategoryTree) Leaves() []*Categories {
+    var leaves []*Categories
+
+    for _, flat := range tree.Flatten(-1) {
+        if flat.IsLeaf() {
+            leaves = append(leaves, flat.Categories)
+        }
+    }
+
+    return leaves
+}
+
+// Flatten lists the categories in depth-first order down to a maximum depth, e.g. to fill a dropdown.
+// A negative maximum depth lists all categories.
+func (tree *CategoryTree) Flatten(maxDepth int) []FlatCategory {
+    var flat []FlatCategory
+
+    for _, root := range tree.roots {
+        flat = append(flat, FlatCategory{Categories: root})
+        flat = append(flat, flatten(root.Subcategories, 1, maxDepth)...)
+    }
+
+    return flat
+}
+
+func flatten(categories []Categories, depth int, maxDepth int) []FlatCategory {
+    var flat []FlatCategory
+
+    if maxDepth >= 0 && depth > maxDepth {
+        return nil
+    }
+
+    for i := range categories {
+        flat = append(flat, FlatCategory{Categories: &categories[i], Depth: depth})
+        flat = append(flat, flatten(categories[i].Subcategories, depth + 1, maxDepth)...)
+    }
+
+    return flat
+}
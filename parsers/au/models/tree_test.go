@@ -0,0 +1,83 @@
+package aumodels_test
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "testing"
+)
+
+func newTree() *aumodels.CategoryTree {
+    return aumodels.NewCategoryTree([]aumodels.Categories{
+        {ID: 0, Name: "All Categories", Slug: "all", ChildrenCount: 2, IsRootCategory: true, Subcategories: []aumodels.Categories{
+            {ID: 18397, Name: "Cars & Vehicles", Slug: "cars-vehicles", ChildrenCount: 1, Subcategories: []aumodels.Categories{
+                {ID: 18320, Name: "Cars, Vans & Utes", Slug: "cars-vans-utes"},
+            }},
+            {ID: 18433, Name: "Home & Garden", Slug: "home-garden", ChildrenCount: 12},
+        }},
+        {ID: 9302, Name: "Jobs", Slug: "jobs", IsRootCategory: true},
+    })
+}
+
+func TestCategoryTreeLookup(t *testing.T) {
+    tree := newTree()
+
+    if tree.Len() != 5 || len(tree.Roots()) != 2 {
+        t.Errorf("unexpected size %d with %d roots", tree.Len(), len(tree.Roots()))
+    }
+
+    if category, ok := tree.BySlug("cars-vans-utes"); !ok || category.ID != 18320 {
+        t.Errorf("unexpected category by slug %+v", category)
+    }
+
+    if parent, ok := tree.Parent(18320); !ok || parent.ID != 18397 {
+        t.Errorf("unexpected parent %+v", parent)
+    }
+
+    if _, ok := tree.Parent(9302); ok {
+        t.Errorf("expected roots to have no parent")
+    }
+
+    if path := tree.Path(18320, " > "); path != "All Categories > Cars & Vehicles > Cars, Vans & Utes" {
+        t.Errorf("unexpected path %q", path)
+    }
+
+    if ancestors := tree.Ancestors(18320); len(ancestors) != 2 || ancestors[0].ID != 18397 || ancestors[1].ID != 0 {
+        t.Errorf("unexpected ancestors %+v", ancestors)
+    }
+
+    if tree.Depth(18320) != 2 || tree.Depth(9302) != 0 || tree.Depth(1) != -1 {
+        t.Errorf("unexpected depths")
+    }
+
+    if tree.Breadcrumb(1) != nil {
+        t.Errorf("expected no breadcrumb for unknown category")
+    }
+}
+
+func TestCategoryTreeTraversal(t *testing.T) {
+    tree := newTree()
+
+    if descendants := tree.Descendants(0); len(descendants) != 3 || descendants[1].ID != 18320 {
+        t.Errorf("unexpected descendants %+v", descendants)
+    }
+
+    if children := tree.Children(0); len(children) != 2 || children[1].ID != 18433 {
+        t.Errorf("unexpected children %+v", children)
+    }
+
+    flat := tree.Flatten(1)
+    if len(flat) != 4 || flat[1].ID != 18397 || flat[1].Depth != 1 || flat[3].ID != 9302 {
+        t.Errorf("unexpected flattened tree %+v", flat)
+    }
+
+    if len(tree.Flatten(-1)) != 5 || len(tree.Flatten(0)) != 2 {
+        t.Errorf("unexpected flattened tree size")
+    }
+
+    if !tree.IsLeaf(18320) || tree.IsLeaf(18433) || tree.IsLeaf(1) {
+        t.Errorf("expected leaves to be detected by children count")
+    }
+
+    if leaves := tree.Leaves(); len(leaves) != 2 || leaves[0].ID != 18320 || leaves[1].ID != 9302 {
+        t.Errorf("unexpected leaves %+v", leaves)
+    }
+}
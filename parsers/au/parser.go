@@ -0,0 +1,53 @@
+package auparser
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/beevik/etree"
+)
+
+// Marketplace is the code of the Australian marketplace in the parser registry
+const Marketplace = "au"
+
+// Parser implements `parsers.Parser` for the Australian marketplace.
+//
+// The registered parser is lenient. To require exact data, register a strict parser in its place:
+//
+//     parsers.Register(auparser.Marketplace, auparser.Parser{Options: []parsers.Option{parsers.Strict()}})
+type Parser struct {
+    Options []parsers.Option // Parsing options applied to every response
+}
+
+func init() {
+    parsers.Register(Marketplace, Parser{})
+}
+
+// ParseAdvert parses a single advertisement into `*aumodels.Advert`
+func (p Parser) ParseAdvert(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseAdvert(doc, p.Options...))
+}
+
+// ParseAdvertList parses a page of advertisements into `*aumodels.Category`
+func (p Parser) ParseAdvertList(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategory(doc, p.Options...))
+}
+
+// ParseCategories parses the category forest into `[]aumodels.Categories`
+func (p Parser) ParseCategories(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategories(doc, p.Options...))
+}
+
+// ParseLocations parses the location forest into `[]aumodels.Location`, implementing `parsers.LocationParser`
+func (p Parser) ParseLocations(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseLocations(doc, p.Options...))
+}
+
+// ParseAttributeSchema parses the attribute metadata of a category into `*aumodels.AttributeSchema`,
+// implementing `parsers.AttributeSchemaParser`
+func (p Parser) ParseAttributeSchema(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseAttributeSchema(doc, p.Options...))
+}
+
+// ParseError parses an `api-base-error` response
+func (Parser) ParseError(doc *etree.Document) *parsers.ErrorDocument {
+    return parsers.ParseErrorDocument(doc)
+}
@@ -0,0 +1,249 @@
+package auparser_test
+
+import (
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/internal/testutil"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "testing"
+    "time"
+)
+
+func TestParseCategoryLenient(t *testing.T) {
+    category, report := auparser.ParseCategory(testutil.ReadFixture(t, "testdata/ads.xml"))
+    if report.IsFatal() || category == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(category.Adverts) != 2 || !category.Adverts[1].Price.Amount.IsZero() {
+        t.Errorf("expected the missing amount to fall back to zero, got %+v", category.Adverts)
+    }
+
+    if len(report.Filter(parsers.SeveritySkippedAdvert)) > 0 {
+        t.Errorf("unexpected skipped adverts: %v", report)
+    }
+}
+
+func TestParseCategoryStrict(t *testing.T) {
+    category, report := auparser.ParseCategory(testutil.ReadFixture(t, "testdata/ads.xml"), parsers.Strict())
+    if report.IsFatal() || category == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(category.Adverts) != 1 || category.Adverts[0].ID != 1 {
+        t.Errorf("expected only advert 1, got %+v", category.Adverts)
+    }
+
+    if skipped := report.Filter(parsers.SeveritySkippedAdvert); len(skipped) != 1 || skipped[0].AdvertID != 2 || skipped[0].Path != "ads/ad/price/amount" || !skipped[0].Required {
+        t.Errorf("expected advert 2 to be skipped for its amount, got %v", skipped)
+    }
+}
+
+func TestParseCategoryCustom(t *testing.T) {
+    category, report := auparser.ParseCategory(testutil.ReadFixture(t, "testdata/ads.xml"), parsers.RequireFields("ads/ad/pictures/thumbnail"))
+    if report.IsFatal() || category == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(category.Adverts) != 1 || category.Adverts[0].ID != 1 || !report.Skipped(2) {
+        t.Errorf("expected advert 2 to be skipped for its thumbnail, got %+v", category.Adverts)
+    }
+}
+
+func TestParseAdvertStrict(t *testing.T) {
+    doc := testutil.ReadFixture(t, "testdata/ads.xml")
+    doc.SetRoot(doc.FindElement("//ad:ad[@id='2']").Copy())
+
+    if advert, report := auparser.ParseAdvert(doc); report.IsFatal() || advert == nil {
+        t.Errorf("expected lenient parsing to succeed: %v", report)
+    }
+
+    if advert, report := auparser.ParseAdvert(doc, parsers.Strict()); !report.IsFatal() || advert != nil {
+        t.Errorf("expected strict parsing to fail, got %+v", advert)
+    }
+
+    strict := auparser.Parser{Options: []parsers.Option{parsers.Strict()}}
+    if model, report := strict.ParseAdvert(doc); model != nil || !report.IsFatal() {
+        t.Errorf("expected strict parser to fail, got %+v", model)
+    }
+}
+
+func TestParseCategories(t *testing.T) {
+    categories, report := auparser.ParseCategories(testutil.ReadFixture(t, "testdata/categories.xml"))
+    if report.IsFatal() {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(categories) != 2 || categories[0].ID != 18397 || categories[1].ID != 18433 || !categories[1].IsRootCategory {
+        t.Fatalf("expected both top-level categories in document order, got %+v", categories)
+    }
+
+    if subcategories := categories[0].Subcategories; len(subcategories) != 2 || subcategories[0].ID != 18320 || subcategories[1].ID != 18322 || subcategories[0].IsRootCategory {
+        t.Errorf("expected the subcategory without ID to be left out, got %+v", subcategories)
+    }
+
+    reported := false
+    for _, err := range report.Filter(parsers.SeverityMissingOptional) {
+        reported = reported || err.Path == "categories/category/18397/category[1]/id"
+    }
+
+    if !reported {
+        t.Errorf("expected the subcategory without ID to be reported, got %v", report)
+    }
+
+    if _, report := auparser.ParseCategories(testutil.ReadFixture(t, "testdata/ads.xml")); !report.IsFatal() {
+        t.Errorf("expected advert list to be rejected")
+    }
+}
+
+func TestParseLocations(t *testing.T) {
+    locations, report := auparser.ParseLocations(testutil.ReadFixture(t, "testdata/locations.xml"))
+    if report.IsFatal() {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(locations) != 2 || locations[0].ID != 0 || locations[1].ID != 3003714 || *locations[1].ParentID != 3003435 {
+        t.Fatalf("expected both top-level locations in document order, got %+v", locations)
+    }
+
+    sydney := locations[0].Sublocations[0].Sublocations[0]
+    if len(sydney.Sublocations) != 1 || sydney.Sublocations[0].Coordinate == nil || *sydney.Sublocations[0].Slug != "sydney-city" {
+        t.Errorf("expected the sublocation without ID to be left out, got %+v", sydney.Sublocations)
+    }
+
+    reported := false
+    for _, err := range report.Filter(parsers.SeverityMissingOptional) {
+        reported = reported || err.Path == "locations/location/3003435/location[1]/id"
+    }
+
+    if !reported {
+        t.Errorf("expected the sublocation without ID to be reported, got %v", report)
+    }
+
+    tree := models.NewLocationTree(locations)
+    if path := tree.Resolve([]models.AdvertLocation{{ID: 3008839}, {ID: 3003714}}); len(path) != 4 || path[0].Name != "Australia" || path[3].Name != "Newtown" {
+        t.Errorf("expected the side-by-side suburb to be resolved through its parent ID, got %+v", path)
+    }
+
+    if _, report := auparser.ParseLocations(testutil.ReadFixture(t, "testdata/categories.xml")); !report.IsFatal() {
+        t.Errorf("expected category document to be rejected")
+    }
+
+    if _, report := auparser.ParseLocations(testutil.ReadFixture(t, "testdata/locations.xml"), parsers.Strict()); report.Err().(*parsers.ParseError).Path != "locations/location/3003435/location[1]/id" {
+        t.Errorf("expected the nested location without ID to reject the response in strict mode, got %v", report)
+    }
+}
+
+func TestParseAttributeSchema(t *testing.T) {
+    schema, report := auparser.ParseAttributeSchema(testutil.ReadFixture(t, "testdata/attributes.xml"))
+    if report.IsFatal() || schema == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if schema.CategoryID != 18320 || len(schema.Attributes) != 4 {
+        t.Fatalf("expected the attribute without name to be left out, got %+v", schema)
+    }
+
+    if carMake := schema.Attributes[0]; !carMake.Required || !carMake.Searchable || carMake.Type != models.AttributeTypeEnum || len(carMake.Values) != 2 || carMake.ValueLabel("mazda") != "Mazda" {
+        t.Errorf("unexpected enumerated attribute %+v", carMake)
+    }
+
+    if odometer := schema.Attributes[2]; odometer.Required || odometer.Searchable || odometer.Type != models.AttributeTypeInteger || *odometer.Unit != "km" {
+        t.Errorf("unexpected attribute with unit %+v", odometer)
+    }
+
+    if vin := schema.Attributes[3]; vin.Type != models.AttributeTypeString || vin.Label != "cars.vin_s" {
+        t.Errorf("expected missing type and label to fall back, got %+v", vin)
+    }
+
+    if searchable := schema.Searchable(); len(searchable) != 2 {
+        t.Errorf("unexpected searchable attributes %+v", searchable)
+    }
+
+    if _, report := auparser.ParseAttributeSchema(testutil.ReadFixture(t, "testdata/categories.xml")); !report.IsFatal() {
+        t.Errorf("expected category document to be rejected")
+    }
+
+    if _, report := auparser.ParseAttributeSchema(testutil.ReadFixture(t, "testdata/attributes.xml"), parsers.Strict()); report.Err().(*parsers.ParseError).Path != "attributes/attribute[4]/name" {
+        t.Errorf("expected the attribute without name to reject the schema in strict mode, got %v", report)
+    }
+}
+
+func TestParseAdvertAttributes(t *testing.T) {
+    advert, report := auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    if report.IsFatal() || advert == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    attributes := advert.Attributes
+    if len(attributes) != 6 {
+        t.Fatalf("unexpected attributes %+v", attributes)
+    }
+
+    if value, err := attributes[0].Enum(); err != nil || value.Slug != "toyota" || value.Label != "Toyota" {
+        t.Errorf("unexpected make %+v: %v", value, err)
+    }
+
+    if year, err := attributes[1].Int(); err != nil || year != 2015 {
+        t.Errorf("unexpected year %d: %v", year, err)
+    }
+
+    if _, err := attributes[1].Bool(); !errors.Is(err, models.ErrAttributeType) {
+        t.Errorf("expected the year not to be a boolean, got %v", err)
+    }
+
+    if registered, err := attributes[3].Bool(); err != nil || !registered {
+        t.Errorf("unexpected registration %v: %v", registered, err)
+    }
+
+    if expiry, err := attributes[4].Time(); err != nil || !expiry.Equal(time.Date(2020, 3, 31, 0, 0, 0, 0, time.UTC)) {
+        t.Errorf("unexpected registration expiry %v: %v", expiry, err)
+    }
+
+    if features := attributes[5]; len(features.Values) != 2 || features.Values[1].Label != "Cruise control" || *features.ValueSlug != "air_conditioning" {
+        t.Errorf("expected every value of the features, got %+v", features)
+    }
+
+    conversions := report.Filter(parsers.SeverityConversion)
+    if len(conversions) != 1 || conversions[0].Path != "ads/ad/attributes[2]/values[0]" || conversions[0].Value != "about 90000" {
+        t.Errorf("expected a conversion error of the odometer, got %v", conversions)
+    }
+
+    if _, err := attributes[2].Int(); err == nil {
+        t.Errorf("expected the odometer not to convert")
+    }
+}
+
+func TestParseAdvertDescriptions(t *testing.T) {
+    advert, report := auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    if report.IsFatal() || advert == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if text := advert.Description; text == nil || *text != "One owner, full service history.\nInspection welcome, see photos." {
+        t.Errorf("unexpected plain text %q", *text)
+    }
+
+    if safe := advert.DescriptionHTML; safe == nil || *safe != "<p>One owner, <b>full service history</b>.<br>Inspection welcome, see photos.</p>" {
+        t.Errorf("unexpected sanitised HTML %q", *safe)
+    }
+
+    if advert.DescriptionExcerptHTML == nil || advert.DescriptionExcerptB64 == nil {
+        t.Errorf("expected all representations by default")
+    }
+
+    advert, _ = auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"), parsers.Descriptions(parsers.DescriptionText), parsers.AllowTags("b"))
+    if advert.DescriptionExcerptHTML != nil || advert.DescriptionExcerptB64 != nil || advert.DescriptionHTML != nil || advert.Description == nil {
+        t.Errorf("expected only plain text, got %+v", advert)
+    }
+
+    advert, _ = auparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"), parsers.Descriptions(parsers.DescriptionSafeHTML), parsers.AllowTags("b"))
+    if safe := advert.DescriptionHTML; safe == nil || *safe != "One owner, <b>full service history</b>.Inspection welcome, see photos." {
+        t.Errorf("unexpected sanitised HTML with custom tags %q", *safe)
+    }
+}
+
+func TestParseUnexpectedResponse(t *testing.T) {
+    testutil.RejectUnexpectedResponses(t, auparser.Parser{}, "testdata")
+}
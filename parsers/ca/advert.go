@@ -0,0 +1,270 @@
+package caparser
+
+import (
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/ca/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+)
+
+// ParseAdvert is to build an Advert model from raw XML response
+func ParseAdvert(doc *etree.Document) (*models.Advert, *parsers.ParseReport) {
+    report := &parsers.ParseReport{}
+
+    if doc == nil {
+        report.Reporter(0).Fatal("", parsers.ErrEmptyResponse)
+        return nil, report
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "ad" || root.Tag != "ad" {
+        report.Reporter(0).Fatal("ads/ad", parsers.ErrUnexpectedResponse)
+        return nil, report
+    }
+
+    if advert := BuildAdvertBase(root, report); advert != nil && !report.IsFatal() {
+        return advert, report
+    }
+
+    if !report.IsFatal() { // a single advertisement that was skipped leaves nothing to return
+        report.Reporter(0).Fatal("ads/ad", fmt.Errorf("advertisement could not be built"))
+    }
+
+    return nil, report
+}
+
+// descriptions limit parsed descriptions to the raw HTML and base64 text kept by the Canadian models
+var descriptions = parsers.NewOptions(parsers.Descriptions(parsers.DescriptionRawHTML | parsers.DescriptionBase64))
+
+// BuildAdvertBase is to build the base of an advertisement
+func BuildAdvertBase(ad *etree.Element, report *parsers.ParseReport) *models.Advert {
+    node := parsers.ParseAdvertNode(ad, report, descriptions)
+    if node == nil {
+        return nil
+    }
+
+    reporter := node.Reporter
+
+    advertPrice := buildPrice(ad, reporter)
+
+    advertPictures := buildPicture(ad, reporter)
+
+    advertAttributes := buildAttribute(ad, reporter)
+
+    return &models.Advert{
+        ID:         node.ID,
+        Type:       u.ReplaceStringWithNil(&node.Type, ""),
+        UserID:     u.ReplaceUintWithNil(&node.UserID, 0),
+        Status:     u.ReplaceStringWithNil(&node.Status, ""),
+        Contact:    buildContact(node.Contact),
+        Category:   buildCategory(node.Category, reporter),
+        Position:   buildPosition(ad, node.Position, reporter),
+        PosterType: u.ReplaceStringWithNil(&node.PosterType, ""),
+        Price:      advertPrice,
+        Title:      node.Title,
+        DescriptionExcerptB64:  node.Description.Base64,
+        DescriptionExcerptHTML: node.Description.RawHTML,
+        Pictures:               advertPictures,
+        Attributes:             advertAttributes,
+        Timestamp:              buildTimestamp(node.Timestamp),
+    }
+}
+
+func buildPrice(ad *etree.Element, reporter parsers.Reporter) *models.AdvertPrice {
+    priceType := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:price/types:price-type/types:value"))(
+        "UNKNOWN", reporter, "ads/ad/price/type")
+
+    priceTypeLabel, _ := extractLocalizedAttr(ad.FindElement(
+        "./ad:price/types:price-type/types:value"), "localized-label")
+
+    currency := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:price/types:currency-iso-code/types:value"))(
+        "", reporter, "ads/ad/price/currency")
+
+    priceAmount := u.FallbackAmountWithReport(
+        u.ExtractTextAsAmount(ad, "./ad:price/types:amount", currency))(
+        money.New(0, currency), reporter, "ads/ad/price/amount")
+
+    currencySymbol := u.FallbackStringWithReport(
+        u.ExtractAttrByTag(ad.FindElement(
+            "./ad:price/types:currency-iso-code/types:value"), "localized-label"))(
+        "", reporter, "ads/ad/price/currency_symbol")
+
+    return &models.AdvertPrice{
+        Type:           &priceType,
+        TypeLabel:      priceTypeLabel,
+        Amount:         &priceAmount,
+        Currency:       u.ReplaceStringWithNil(&currency, ""),
+        CurrencySymbol: u.ReplaceStringWithNil(&currencySymbol, ""),
+    }
+}
+
+func buildCategory(cat *parsers.AdvertCategoryNode, reporter parsers.Reporter) *models.AdvertCategory {
+    if cat == nil {
+        return nil
+    }
+
+    catName := fallbackLocalizedWithReport(
+        extractLocalizedText(cat.Element, "./cat:localized-name"))(
+        reporter, "ads/ad/category/name")
+
+    return &models.AdvertCategory{
+        ID:            cat.ID,
+        Name:          catName,
+        Slug:          u.ReplaceStringWithNil(&cat.Slug, ""),
+        ParentSlug:    u.ReplaceStringWithNil(&cat.ParentSlug, ""),
+        ChildrenCount: &cat.ChildrenCount,
+    }
+}
+
+func buildPosition(ad *etree.Element, position parsers.PositionNode, reporter parsers.Reporter) *models.AdvertPosition {
+    var coordinate *models.AdvertCoordinate
+    var locations []models.AdvertLocation
+
+    postalCode, _ := u.ExtractText(ad, "./ad:ad-address/types:zip-code")
+
+    provinceCode, province := resolveProvince(ad.FindElement("./ad:ad-address/types:state"))
+    if province.IsEmpty() {
+        reporter.Report("ads/ad/positions/province", u.ErrElementMissing)
+    }
+
+    if position.Coordinate != nil {
+        coordinate = &models.AdvertCoordinate{
+            Longitude: position.Coordinate.Longitude,
+            Latitude:  position.Coordinate.Latitude,
+        }
+    }
+
+    for i, loc := range position.Locations {
+        locName := fallbackLocalizedWithReport(
+            extractLocalizedText(loc.Element, "./loc:localized-name"))(
+            reporter, fmt.Sprintf("ads/ad/positions/location[%d]/name", i))
+
+        locations = append(locations, models.AdvertLocation{
+            ID:         loc.ID,
+            Name:       locName,
+            ParentID:   u.ReplaceUintWithNil(&loc.ParentID, 0),
+        })
+    }
+
+    return &models.AdvertPosition{
+        Address:      u.ReplaceStringWithNil(&position.Address, ""),
+        City:         u.ReplaceStringWithNil(&position.City, ""),
+        PostalCode:   u.ReplaceStringWithNil(&postalCode, ""),
+        ProvinceCode: u.ReplaceStringWithNil(&provinceCode, ""),
+        Province:     province,
+        Country:      u.ReplaceStringWithNil(&position.Country, ""),
+        Coordinate:   coordinate,
+        Locations:    locations,
+    }
+}
+
+// resolveProvince resolves the state of an address, given as either postal abbreviation or name,
+// to the abbreviation and bilingual name of the province
+func resolveProvince(state *etree.Element) (string, models.LocalizedText) {
+    if state == nil {
+        return "", models.LocalizedText{}
+    }
+
+    value := strings.TrimSpace(state.Text())
+
+    if province, ok := models.Provinces[strings.ToUpper(value)]; ok {
+        return strings.ToUpper(value), province
+    }
+
+    for code, province := range models.Provinces {
+        if strings.EqualFold(value, province.English) || strings.EqualFold(value, province.French) {
+            return code, province
+        }
+    }
+
+    var province models.LocalizedText
+    province.Set(languageOf(state), value) // unknown province, keep as is
+
+    return "", province
+}
+
+func buildPicture(ad *etree.Element, reporter parsers.Reporter) []models.AdvertPicture {
+    var pictures []models.AdvertPicture
+
+    for i, pic := range ad.FindElements("./pic:pictures/pic:picture") {
+        link := func(rel string) *string {
+            href := u.FallbackStringWithReport(
+                u.ExtractAttrByTag(pic.FindElement(fmt.Sprintf("./pic:link[@rel='%s']", rel)), "href"))(
+                "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/%s", i, rel))
+
+            return u.ReplaceStringWithNil(&href, "")
+        }
+
+        pictures = append(pictures, models.AdvertPicture{
+            Thumbnail:    link("thumbnail"),
+            Normal:       link("normal"),
+            Large:        link("large"),
+            ExtraLarge:   link("extraLarge"),
+            Extra2XLarge: link("extraExtraLarge"),
+        })
+    }
+
+    return pictures
+}
+
+// buildAttribute builds the attributes of an advertisement, merging the labels of an attribute
+// repeated for each language in bilingual responses
+func buildAttribute(ad *etree.Element, reporter parsers.Reporter) []models.AdvertAttribute {
+    var attributes []models.AdvertAttribute
+    indices := make(map[string]int)
+
+    for i, attr := range ad.FindElements("./attr:attributes/attr:attribute") {
+        keySlug := u.FallbackStringWithReport(
+            u.ExtractAttrByTag(attr, "name"))(
+            "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_slug", i))
+        keyName := fallbackLocalizedWithReport(
+            extractLocalizedAttr(attr, "localized-label"))(
+            reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_name", i))
+        valueType := u.FallbackStringWithReport(
+            u.ExtractAttrByTag(attr, "type"))(
+            "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/value_type", i))
+
+        valueSlug, _ := u.ExtractText(attr, "./attr:value")
+        valueName, _ := extractLocalizedAttr(attr.FindElement("./attr:value"), "localized-label")
+
+        if j, repeated := indices[keySlug]; repeated && keySlug != "" { // the same attribute in another language
+            mergeLocalized(&attributes[j].KeyName, keyName)
+            mergeLocalized(&attributes[j].ValueName, valueName)
+            continue
+        }
+
+        indices[keySlug] = len(attributes)
+
+        attributes = append(attributes, models.AdvertAttribute{
+            KeySlug:    keySlug,
+            KeyName:    keyName,
+            ValueType:  u.ReplaceStringWithNil(&valueType, ""),
+            ValueSlug:  u.ReplaceStringWithNil(&valueSlug, ""),
+            ValueName:  valueName,
+        })
+    }
+
+    return attributes
+}
+
+func buildTimestamp(timestamp parsers.TimestampNode) models.AdvertTimestamp {
+    return models.AdvertTimestamp{
+        CreationTime:       timestamp.CreationTime,
+        ModificationTime:   timestamp.ModificationTime,
+        StartTime:          timestamp.StartTime,
+        EndTime:            timestamp.EndTime,
+    }
+}
+
+func buildContact(contact parsers.ContactNode) *models.AdvertContact {
+    return &models.AdvertContact{
+        Name:   u.ReplaceStringWithNil(&contact.Name, ""),
+        Phone:  u.ReplaceStringWithNil(&contact.Phone, ""),
+    }
+}
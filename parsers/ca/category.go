@@ -0,0 +1,31 @@
+package caparser
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/ca/models"
+    "github.com/beevik/etree"
+)
+
+// ParseCategory is to build a Category models from raw XML response
+func ParseCategory(doc *etree.Document) (*models.Category, *parsers.ParseReport) {
+    report := &parsers.ParseReport{}
+
+    var adverts []models.Advert
+
+    root := parsers.WalkAdverts(doc, report, func(advert *etree.Element) {
+        if builtAdvert := BuildAdvertBase(advert, report); builtAdvert != nil { // nil skips the current ad
+            adverts = append(adverts, *builtAdvert)
+        }
+    })
+
+    if root == nil {
+        return nil, report
+    }
+
+    pagination := models.CategoryPagination(parsers.ParsePagination(root, report.Reporter(0)))
+
+    return &models.Category{
+        Adverts:        adverts,
+        Pagination:     &pagination,
+    }, report
+}
@@ -0,0 +1,78 @@
+package caparser
+
+import (
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/ca/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+)
+
+// languageOf returns the language of an element as declared by `xml:lang` on the element or its ancestors,
+// English by default
+func languageOf(element *etree.Element) string {
+    for ; element != nil; element = element.Parent() {
+        if lang := element.SelectAttrValue("xml:lang", ""); lang != "" {
+            return strings.ToLower(strings.SplitN(strings.Replace(lang, "_", "-", -1), "-", 2)[0]) // e.g. fr-CA
+        }
+    }
+
+    return models.English
+}
+
+// extractLocalizedText collects the texts of all elements matching the path by their language,
+// as labels are repeated for each language in bilingual responses
+func extractLocalizedText(element *etree.Element, path string) (models.LocalizedText, error) {
+    var text models.LocalizedText
+
+    if element != nil {
+        for _, result := range element.FindElements(path) {
+            text.Set(languageOf(result), strings.TrimSpace(result.Text()))
+        }
+    }
+
+    if text.IsEmpty() {
+        return text, u.ErrElementMissing
+    }
+
+    return text, nil
+}
+
+// extractLocalizedAttr reads an attribute as a label in the language of the element
+func extractLocalizedAttr(element *etree.Element, tag string) (models.LocalizedText, error) {
+    var text models.LocalizedText
+
+    if element != nil {
+        if attr := element.SelectAttr(tag); attr != nil {
+            text.Set(languageOf(element), strings.TrimSpace(attr.Value))
+        }
+    }
+
+    if text.IsEmpty() {
+        return text, u.ErrAttributeMissing
+    }
+
+    return text, nil
+}
+
+// fallbackLocalizedWithReport mirrors `FallbackStringWithReport` for localized labels
+func fallbackLocalizedWithReport(value models.LocalizedText, err error) func(reporter u.ErrorReporter, path string) models.LocalizedText {
+    return func(reporter u.ErrorReporter, path string) models.LocalizedText {
+        if err != nil { // fallback is necessary
+            reporter.Report(path, err)
+            return models.LocalizedText{}
+        }
+
+        return value
+    }
+}
+
+// mergeLocalized fills the languages missing from a label with those of another label
+func mergeLocalized(into *models.LocalizedText, from models.LocalizedText) {
+    if into.English == "" {
+        into.English = from.English
+    }
+
+    if into.French == "" {
+        into.French = from.French
+    }
+}
@@ -0,0 +1,13 @@
+package camodels
+
+// Categories are information about categories and subcategories
+type Categories struct {
+    ID                      uint                `json:"id"`
+    Name                    LocalizedText       `json:"name"`
+    Slug                    string              `json:"slug"`
+    ParentID                *uint               `json:"parent_id"`
+    ParentSlug              *string             `json:"parent_slug"`
+    ChildrenCount           uint                `json:"children_count"`
+    Subcategories           []Categories        `json:"subcategories,omitempty"`
+    IsRootCategory          bool                `json:"is_root"`
+}
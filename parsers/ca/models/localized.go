@@ -0,0 +1,42 @@
+package camodels
+
+// Languages of localized labels
+const (
+    English = "en"
+    French  = "fr"
+)
+
+// LocalizedText is a label available in English and/or French
+type LocalizedText struct {
+    English                 string              `json:"en,omitempty"`
+    French                  string              `json:"fr,omitempty"`
+}
+
+// In returns the label in the given language, falling back to the other language if it is unavailable
+func (text LocalizedText) In(language string) string {
+    if language == French && text.French != "" || text.English == "" {
+        return text.French
+    }
+
+    return text.English
+}
+
+// String returns the English label, or the French one if there is no English label
+func (text LocalizedText) String() string {
+    return text.In(English)
+}
+
+// IsEmpty reports whether the label is unavailable in both languages
+func (text LocalizedText) IsEmpty() bool {
+    return text.English == "" && text.French == ""
+}
+
+// Set assigns the label of a language, ignoring unknown languages
+func (text *LocalizedText) Set(language string, label string) {
+    switch language {
+    case English:
+        text.English = label
+    case French:
+        text.French = label
+    }
+}
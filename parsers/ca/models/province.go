@@ -0,0 +1,18 @@
+package camodels
+
+// Provinces are the names of Canadian provinces and territories by their postal abbreviation
+var Provinces = map[string]LocalizedText{
+    "AB": {English: "Alberta", French: "Alberta"},
+    "BC": {English: "British Columbia", French: "Colombie-Britannique"},
+    "MB": {English: "Manitoba", French: "Manitoba"},
+    "NB": {English: "New Brunswick", French: "Nouveau-Brunswick"},
+    "NL": {English: "Newfoundland and Labrador", French: "Terre-Neuve-et-Labrador"},
+    "NS": {English: "Nova Scotia", French: "Nouvelle-Écosse"},
+    "NT": {English: "Northwest Territories", French: "Territoires du Nord-Ouest"},
+    "NU": {English: "Nunavut", French: "Nunavut"},
+    "ON": {English: "Ontario", French: "Ontario"},
+    "PE": {English: "Prince Edward Island", French: "Île-du-Prince-Édouard"},
+    "QC": {English: "Quebec", French: "Québec"},
+    "SK": {English: "Saskatchewan", French: "Saskatchewan"},
+    "YT": {English: "Yukon", French: "Yukon"},
+}
@@ -0,0 +1,36 @@
+package caparser
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/beevik/etree"
+)
+
+// Marketplace is the code of the Canadian marketplace in the parser registry
+const Marketplace = "ca"
+
+// Parser implements `parsers.Parser` for the Canadian marketplace
+type Parser struct{}
+
+func init() {
+    parsers.Register(Marketplace, Parser{})
+}
+
+// ParseAdvert parses a single advertisement into `*camodels.Advert`
+func (Parser) ParseAdvert(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseAdvert(doc))
+}
+
+// ParseAdvertList parses a page of advertisements into `*camodels.Category`
+func (Parser) ParseAdvertList(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategory(doc))
+}
+
+// ParseCategories parses the category forest into `[]camodels.Categories`
+func (Parser) ParseCategories(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategories(doc))
+}
+
+// ParseError parses an `api-base-error` response
+func (Parser) ParseError(doc *etree.Document) *parsers.ErrorDocument {
+    return parsers.ParseErrorDocument(doc)
+}
@@ -0,0 +1,106 @@
+package caparser_test
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/internal/testutil"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/ca"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/ca/models"
+    "testing"
+    "time"
+)
+
+func TestParseAdvert(t *testing.T) {
+    advert, report := caparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    if report.IsFatal() || advert == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if report.Len() > 0 {
+        t.Errorf("unexpected errors: %v", report)
+    }
+
+    if advert.ID != 1423456789 || advert.Title != "Vélo de montagne Trek" {
+        t.Errorf("unexpected advert %d %q", advert.ID, advert.Title)
+    }
+
+    if price := advert.Price; price.Amount.Minor != 34999 || price.Format() != "$349.99" || *price.Currency != "CAD" || *price.CurrencySymbol != "$" || price.TypeLabel.French != "Prix fixe" || price.TypeLabel.English != "" {
+        t.Errorf("unexpected price %+v", price)
+    }
+
+    position := advert.Position
+    if *position.PostalCode != "H3A 3G4" || *position.ProvinceCode != "QC" || position.Province.French != "Québec" || position.Province.English != "Quebec" {
+        t.Errorf("unexpected position %+v", position)
+    }
+
+    if len(position.Locations) != 1 || position.Locations[0].Name.In(camodels.French) != "Montréal" || position.Locations[0].Name.String() != "Montreal" {
+        t.Errorf("unexpected locations %+v", position.Locations)
+    }
+
+    if name := advert.Category.Name; name.English != "Bikes" || name.French != "Vélos" {
+        t.Errorf("unexpected category name %+v", name)
+    }
+
+    if attrs := advert.Attributes; len(attrs) != 2 || attrs[0].KeyName.French != "Type" || attrs[0].ValueName.French != "Montagne" {
+        t.Fatalf("unexpected attributes %+v", attrs)
+    }
+
+    if forSaleBy := advert.Attributes[1]; forSaleBy.KeyName.English != "For sale by" || forSaleBy.KeyName.French != "À vendre par" || forSaleBy.ValueName.English != "Owner" || forSaleBy.ValueName.French != "Propriétaire" {
+        t.Errorf("expected the labels of the repeated attribute to be merged, got %+v", forSaleBy)
+    }
+
+    if len(advert.Pictures) != 1 || *advert.Pictures[0].Extra2XLarge != "https://img.example.com/1/extraExtraLarge.jpg" {
+        t.Errorf("unexpected pictures %+v", advert.Pictures)
+    }
+
+    if created := advert.Timestamp.CreationTime; created == nil || !created.Equal(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)) {
+        t.Errorf("unexpected creation time %v", created)
+    }
+}
+
+func TestParseCategory(t *testing.T) {
+    category, report := caparser.ParseCategory(testutil.ReadFixture(t, "testdata/ads.xml"))
+    if report.IsFatal() || category == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(category.Adverts) != 2 || category.Adverts[0].ID != 1 || category.Adverts[1].ID != 3 {
+        t.Fatalf("expected adverts 1 and 3, got %+v", category.Adverts)
+    }
+
+    if position := category.Adverts[0].Position; *position.ProvinceCode != "ON" {
+        t.Errorf("expected Ontario to be resolved by its English name, got %+v", position)
+    }
+
+    if position := category.Adverts[1].Position; *position.ProvinceCode != "BC" || position.Province.English != "British Columbia" {
+        t.Errorf("expected British Columbia to be resolved by its French name, got %+v", position)
+    }
+
+    if p := category.Pagination; p.CurrentPage != 0 || p.PageSize != 3 || p.EntrySize != 42 {
+        t.Errorf("unexpected pagination %+v", p)
+    }
+}
+
+func TestParseCategories(t *testing.T) {
+    categories, report := caparser.ParseCategories(testutil.ReadFixture(t, "testdata/categories.xml"))
+    if report.IsFatal() || len(categories) != 1 {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    root := categories[0]
+
+    if !root.IsRootCategory || len(root.Subcategories) != 2 || root.Name.French != "Toutes les catégories" {
+        t.Fatalf("unexpected root category %+v", root)
+    }
+
+    bikes := root.Subcategories[0].Subcategories[0]
+    if bikes.Slug != "bikes" || bikes.Name.English != "Bikes" || bikes.Name.French != "Vélos" || *bikes.ParentID != 10 {
+        t.Errorf("unexpected subcategory %+v", bikes)
+    }
+
+    if cars := root.Subcategories[1]; cars.Name.English != "Cars & Vehicles" || cars.Name.In(camodels.French) != "Cars & Vehicles" {
+        t.Errorf("expected unlabelled name to default to English, got %+v", cars.Name)
+    }
+}
+
+func TestParseUnexpectedResponse(t *testing.T) {
+    testutil.RejectUnexpectedResponses(t, caparser.Parser{}, "testdata")
+}
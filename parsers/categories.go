@@ -0,0 +1,119 @@
+package parsers
+
+import (
+    "fmt"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+)
+
+// CategoryNode is a category of a category response with the fields shared by all marketplaces,
+// from which marketplace parsers build their models. Names are left to the marketplace as they may be localised.
+type CategoryNode struct {
+    Element       *etree.Element // element of the category, for marketplace-specific fields
+    ID            uint
+    Slug          string
+    ParentID      *uint          // nil if missing or invalid, which is reported
+    ParentSlug    *string        // nil if missing, which is reported
+    ChildrenCount uint
+    Subcategories []CategoryNode // in document order
+    IsRoot        bool           // a top-level category of the response
+}
+
+// Path returns the path of the category in reports, e.g. `categories/category/18320`
+func (node CategoryNode) Path() string {
+    return fmt.Sprintf("categories/category/%d", node.ID)
+}
+
+// ParseCategoryForest is to build every category of a raw XML response as a forest in document order.
+// A `cat:categories` response may hold several top-level categories, each being the root of its own tree.
+// Categories without an ID are left out, and the response is rejected if no category could be built.
+func ParseCategoryForest(doc *etree.Document, reporter Reporter) []CategoryNode {
+    if doc == nil {
+        reporter.Fatal("", ErrEmptyResponse)
+        return nil
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "cat" {
+        reporter.Fatal("categories", ErrUnexpectedResponse)
+        return nil
+    }
+
+    var rootCategories []*etree.Element
+
+    switch root.Tag {
+    case "categories": // contains multiple top-level categories
+        rootCategories = root.SelectElements("category")
+    case "category": // contains a single category
+        rootCategories = []*etree.Element{ root }
+    default:
+        reporter.Fatal("categories", ErrUnexpectedResponse)
+        return nil
+    }
+
+    nodes := buildCategoryForest(rootCategories, "categories", reporter)
+
+    if len(nodes) < 1 {
+        reporter.Fatal("categories/category", fmt.Errorf("no category could be built"))
+        return nil
+    }
+
+    for i := range nodes {
+        nodes[i].IsRoot = true
+    }
+
+    return nodes
+}
+
+// buildCategoryForest builds sibling categories in document order, leaving out malformed ones
+func buildCategoryForest(elements []*etree.Element, path string, reporter Reporter) []CategoryNode {
+    var nodes []CategoryNode
+
+    for i, element := range elements {
+        if node := buildCategoryNode(element, fmt.Sprintf("%s/category[%d]", path, i), reporter); node != nil {
+            nodes = append(nodes, *node)
+        }
+    }
+
+    return nodes
+}
+
+func buildCategoryNode(category *etree.Element, path string, reporter Reporter) *CategoryNode {
+    if category == nil {
+        return nil
+    }
+
+    catID, err := u.ConvString2Uint(u.ExtractAttrByTag(category, "id"))
+    if err != nil {
+        reporter.Report(path + "/id", err)
+        return nil
+    }
+
+    node := CategoryNode{Element: category, ID: catID, IsRoot: catID <= 0}
+
+    node.Slug = u.FallbackStringWithReport(
+        u.ExtractText(category, "./cat:id-name"))(
+        "", reporter, node.Path() + "/slug")
+
+    if catParentID, err := u.ExtractTextAsUint(category, "./cat:parent-id"); err == nil {
+        node.ParentID = &catParentID
+    } else {
+        reporter.Report(node.Path() + "/parent_id", err)
+    }
+
+    if catParentSlug, err := u.ExtractText(category, "./cat:l1-name"); err == nil {
+        node.ParentSlug = &catParentSlug
+    } else {
+        reporter.Report(node.Path() + "/parent_slug", err)
+    }
+
+    node.ChildrenCount = u.FallbackUintWithReport(
+        u.ExtractTextAsUint(category, "./cat:children-count"))(
+        0, reporter, node.Path() + "/children_count")
+
+    // recursively add subcategories
+    node.Subcategories = buildCategoryForest(category.FindElements("./cat:category"), node.Path(), reporter)
+
+    return &node
+}
@@ -0,0 +1,272 @@
+package deparser
+
+import (
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/de/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "strings"
+)
+
+// PriceTypeNegotiable is the price type of ads with a negotiable price ("VB", Verhandlungsbasis)
+const PriceTypeNegotiable = "NEGOTIABLE"
+
+// Currency is the currency of the marketplace, applied to shipping prices which are given without one
+const Currency = "EUR"
+
+// ParseAdvert is to build an Advert model from raw XML response
+func ParseAdvert(doc *etree.Document) (*models.Advert, *parsers.ParseReport) {
+    report := &parsers.ParseReport{}
+
+    if doc == nil {
+        report.Reporter(0).Fatal("", parsers.ErrEmptyResponse)
+        return nil, report
+    }
+
+    root := doc.Root()
+
+    if root == nil || root.Space != "ad" || root.Tag != "ad" {
+        report.Reporter(0).Fatal("ads/ad", parsers.ErrUnexpectedResponse)
+        return nil, report
+    }
+
+    if advert := BuildAdvertBase(root, report); advert != nil && !report.IsFatal() {
+        return advert, report
+    }
+
+    if !report.IsFatal() { // a single advertisement that was skipped leaves nothing to return
+        report.Reporter(0).Fatal("ads/ad", fmt.Errorf("advertisement could not be built"))
+    }
+
+    return nil, report
+}
+
+// descriptions are the options producing the representations of a description held by the German models
+var descriptions = parsers.NewOptions(parsers.Descriptions(parsers.DescriptionRawHTML | parsers.DescriptionBase64))
+
+// BuildAdvertBase is to build the base of an advertisement
+func BuildAdvertBase(ad *etree.Element, report *parsers.ParseReport) *models.Advert {
+    node := parsers.ParseAdvertNode(ad, report, descriptions)
+    if node == nil {
+        return nil
+    }
+
+    reporter := node.Reporter
+
+    advertPrice := buildPrice(ad, reporter)
+
+    advertPictures := buildPicture(ad, reporter)
+
+    advertAttributes := buildAttribute(ad, reporter)
+    advertShippingOptions := buildShippingOption(ad, reporter)
+
+    return &models.Advert{
+        ID:         node.ID,
+        Type:       u.ReplaceStringWithNil(&node.Type, ""),
+        UserID:     u.ReplaceUintWithNil(&node.UserID, 0),
+        Status:     u.ReplaceStringWithNil(&node.Status, ""),
+        Contact:    buildContact(node.Contact),
+        Category:   buildCategory(node.Category, reporter),
+        Position:   buildPosition(ad, node.Position, reporter),
+        PosterType: u.ReplaceStringWithNil(&node.PosterType, ""),
+        Price:      advertPrice,
+        Title:      node.Title,
+        DescriptionExcerptB64:  node.Description.Base64,
+        DescriptionExcerptHTML: node.Description.RawHTML,
+        Pictures:               advertPictures,
+        Attributes:             advertAttributes,
+        ShippingOptions:        advertShippingOptions,
+        Timestamp:              buildTimestamp(node.Timestamp),
+    }
+}
+
+func buildPrice(ad *etree.Element, reporter parsers.Reporter) *models.AdvertPrice {
+    priceType := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:price/types:price-type/types:value"))(
+        "UNKNOWN", reporter, "ads/ad/price/type")
+
+    priceTypeLabel, _ := u.ExtractAttrByTag(ad.FindElement(
+        "./ad:price/types:price-type/types:value"), "localized-label")
+
+    currency := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:price/types:currency-iso-code/types:value"))(
+        "", reporter, "ads/ad/price/currency")
+
+    priceAmount := u.FallbackAmountWithReport(
+        u.ExtractTextAsAmount(ad, "./ad:price/types:amount", currency))(
+        money.New(0, currency), reporter, "ads/ad/price/amount")
+
+    currencySymbol := u.FallbackStringWithReport(
+        u.ExtractAttrByTag(ad.FindElement(
+            "./ad:price/types:currency-iso-code/types:value"), "localized-label"))(
+        "", reporter, "ads/ad/price/currency_symbol")
+
+    return &models.AdvertPrice{
+        Type:           &priceType,
+        TypeLabel:      u.ReplaceStringWithNil(&priceTypeLabel, ""),
+        Amount:         &priceAmount,
+        Currency:       u.ReplaceStringWithNil(&currency, ""),
+        CurrencySymbol: u.ReplaceStringWithNil(&currencySymbol, ""),
+        IsNegotiable:   priceType == PriceTypeNegotiable || strings.TrimSpace(priceTypeLabel) == "VB",
+    }
+}
+
+func buildCategory(cat *parsers.AdvertCategoryNode, reporter parsers.Reporter) *models.AdvertCategory {
+    if cat == nil {
+        return nil
+    }
+
+    catName := u.FallbackStringWithReport(
+        u.ExtractText(cat.Element, "./cat:localized-name"))(
+        "", reporter, "ads/ad/category/name")
+
+    return &models.AdvertCategory{
+        ID:            cat.ID,
+        Name:          catName,
+        Slug:          u.ReplaceStringWithNil(&cat.Slug, ""),
+        ParentSlug:    u.ReplaceStringWithNil(&cat.ParentSlug, ""),
+        ChildrenCount: &cat.ChildrenCount,
+    }
+}
+
+func buildPosition(ad *etree.Element, position parsers.PositionNode, reporter parsers.Reporter) *models.AdvertPosition {
+    var coordinate *models.AdvertCoordinate
+    var locations []models.AdvertLocation
+
+    zipCode := u.FallbackStringWithReport(
+        u.ExtractText(ad, "./ad:ad-address/types:zip-code"))(
+        "", reporter, "ads/ad/positions/zip_code")
+
+    if position.Coordinate != nil {
+        coordinate = &models.AdvertCoordinate{
+            Longitude: position.Coordinate.Longitude,
+            Latitude:  position.Coordinate.Latitude,
+        }
+    }
+
+    for i, loc := range position.Locations {
+        locName := u.FallbackStringWithReport(
+            u.ExtractText(loc.Element, "./loc:localized-name"))(
+            "", reporter, fmt.Sprintf("ads/ad/positions/location[%d]/name", i))
+
+        locations = append(locations, models.AdvertLocation{
+            ID:         loc.ID,
+            Name:       locName,
+            ParentID:   u.ReplaceUintWithNil(&loc.ParentID, 0),
+        })
+    }
+
+    return &models.AdvertPosition{
+        ZipCode:    u.ReplaceStringWithNil(&zipCode, ""),
+        City:       u.ReplaceStringWithNil(&position.City, ""),
+        State:      u.ReplaceStringWithNil(&position.State, ""),
+        Country:    u.ReplaceStringWithNil(&position.Country, ""),
+        Coordinate: coordinate,
+        Locations:  locations,
+    }
+}
+
+func buildPicture(ad *etree.Element, reporter parsers.Reporter) []models.AdvertPicture {
+    var pictures []models.AdvertPicture
+
+    for i, pic := range ad.FindElements("./pic:pictures/pic:picture") {
+        link := func(rel string) *string {
+            href := u.FallbackStringWithReport(
+                u.ExtractAttrByTag(pic.FindElement(fmt.Sprintf("./pic:link[@rel='%s']", rel)), "href"))(
+                "", reporter, fmt.Sprintf("ads/ad/pictures[%d]/%s", i, rel))
+
+            return u.ReplaceStringWithNil(&href, "")
+        }
+
+        pictures = append(pictures, models.AdvertPicture{
+            Thumbnail:  link("thumbnail"),
+            Teaser:     link("teaser"),
+            Large:      link("large"),
+            ExtraLarge: link("extraLarge"),
+            XXL:        link("XXL"),
+        })
+    }
+
+    return pictures
+}
+
+func buildAttribute(ad *etree.Element, reporter parsers.Reporter) []models.AdvertAttribute {
+    var attributes []models.AdvertAttribute
+
+    for i, attr := range ad.FindElements("./attr:attributes/attr:attribute") {
+        keySlug := u.FallbackStringWithReport(
+            u.ExtractAttrByTag(attr, "name"))(
+            "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_slug", i))
+        keyName := u.FallbackStringWithReport(
+            u.ExtractAttrByTag(attr, "localized-label"))(
+            "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/key_name", i))
+        valueType := u.FallbackStringWithReport(
+            u.ExtractAttrByTag(attr, "type"))(
+            "", reporter, fmt.Sprintf("ads/ad/attributes[%d]/value_type", i))
+
+        valueSlug, _ := u.ExtractText(attr, "./attr:value")
+        valueName, _ := u.ExtractAttrByTag(attr.FindElement("./attr:value"), "localized-label")
+
+        attributes = append(attributes, models.AdvertAttribute{
+            KeySlug:    keySlug,
+            KeyName:    keyName,
+            ValueType:  u.ReplaceStringWithNil(&valueType, ""),
+            ValueSlug:  u.ReplaceStringWithNil(&valueSlug, ""),
+            ValueName:  u.ReplaceStringWithNil(&valueName, ""),
+        })
+    }
+
+    return attributes
+}
+
+func buildShippingOption(ad *etree.Element, reporter parsers.Reporter) []models.AdvertShippingOption {
+    var options []models.AdvertShippingOption
+
+    for i, option := range ad.FindElements("./ad:shipping-options/ad:shipping-option") {
+        optionID, err := u.ExtractText(option, "./ad:id")
+        if err != nil || optionID == "" {
+            if err == nil {
+                err = u.ErrElementMissing
+            }
+
+            reporter.Report(fmt.Sprintf("ads/ad/shipping_options[%d]/id", i), err)
+            continue
+        }
+
+        optionName, _ := u.ExtractText(option, "./ad:localized-name")
+
+        var optionAmount *money.Amount
+        if amount, err := u.ExtractTextAsAmount(option, "./ad:price", Currency); err == nil {
+            optionAmount = &amount
+        } else if !errors.Is(err, u.ErrElementMissing) { // free shipping options have no price
+            reporter.Report(fmt.Sprintf("ads/ad/shipping_options[%d]/price", i), err)
+        }
+
+        options = append(options, models.AdvertShippingOption{
+            ID:     optionID,
+            Name:   u.ReplaceStringWithNil(&optionName, ""),
+            Amount: optionAmount,
+        })
+    }
+
+    return options
+}
+
+func buildTimestamp(timestamp parsers.TimestampNode) models.AdvertTimestamp {
+    return models.AdvertTimestamp{
+        CreationTime:       timestamp.CreationTime,
+        ModificationTime:   timestamp.ModificationTime,
+        StartTime:          timestamp.StartTime,
+        EndTime:            timestamp.EndTime,
+    }
+}
+
+func buildContact(contact parsers.ContactNode) *models.AdvertContact {
+    return &models.AdvertContact{
+        Name:   u.ReplaceStringWithNil(&contact.Name, ""),
+        Phone:  u.ReplaceStringWithNil(&contact.Phone, ""),
+    }
+}
@@ -0,0 +1,46 @@
+package deparser
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    models "github.com/GreenVine/ebay-classifieds-api/parsers/de/models"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+)
+
+// ParseCategories is to build every category of a raw XML response as a forest in document order,
+// see `parsers.ParseCategoryForest`
+func ParseCategories(doc *etree.Document) ([]models.Categories, *parsers.ParseReport) {
+    report := &parsers.ParseReport{}
+    reporter := report.Reporter(0)
+
+    nodes := parsers.ParseCategoryForest(doc, reporter)
+    if report.IsFatal() {
+        return nil, report
+    }
+
+    return buildCategories(nodes, reporter), report
+}
+
+// buildCategories builds the models of sibling categories and their subcategories
+func buildCategories(nodes []parsers.CategoryNode, reporter parsers.Reporter) []models.Categories {
+    var categories []models.Categories
+
+    for _, node := range nodes {
+        catName := u.FallbackStringWithReport(
+            u.ExtractText(node.Element, "./cat:localized-name"))(
+            "", reporter, node.Path() + "/name")
+
+        categories = append(categories, models.Categories{
+            ID:             node.ID,
+            Name:           catName,
+            Slug:           node.Slug,
+            ParentID:       node.ParentID,
+            ParentSlug:     node.ParentSlug,
+            ChildrenCount:  node.ChildrenCount,
+            Subcategories:  buildCategories(node.Subcategories, reporter),
+            IsRootCategory: node.IsRoot,
+        })
+    }
+
+    return categories
+}
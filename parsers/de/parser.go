@@ -0,0 +1,36 @@
+package deparser
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/beevik/etree"
+)
+
+// Marketplace is the code of the German marketplace in the parser registry
+const Marketplace = "de"
+
+// Parser implements `parsers.Parser` for the German marketplace
+type Parser struct{}
+
+func init() {
+    parsers.Register(Marketplace, Parser{})
+}
+
+// ParseAdvert parses a single advertisement into `*demodels.Advert`
+func (Parser) ParseAdvert(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseAdvert(doc))
+}
+
+// ParseAdvertList parses a page of advertisements into `*demodels.Category`
+func (Parser) ParseAdvertList(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategory(doc))
+}
+
+// ParseCategories parses the category forest into `[]demodels.Categories`
+func (Parser) ParseCategories(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    return parsers.Model(ParseCategories(doc))
+}
+
+// ParseError parses an `api-base-error` response
+func (Parser) ParseError(doc *etree.Document) *parsers.ErrorDocument {
+    return parsers.ParseErrorDocument(doc)
+}
@@ -0,0 +1,97 @@
+package deparser_test
+
+import (
+    "github.com/GreenVine/ebay-classifieds-api/internal/testutil"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/de"
+    "testing"
+    "time"
+)
+
+func TestParseAdvert(t *testing.T) {
+    advert, report := deparser.ParseAdvert(testutil.ReadFixture(t, "testdata/advert.xml"))
+    if report.IsFatal() || advert == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if report.Len() > 0 {
+        t.Errorf("unexpected errors: %v", report)
+    }
+
+    if advert.ID != 1234567890 || advert.Title != "Gazelle Damenfahrrad 28 Zoll" {
+        t.Errorf("unexpected advert %d %q", advert.ID, advert.Title)
+    }
+
+    if price := advert.Price; price.Amount.Minor != 24900 || price.Format() != "€249.00" || *price.Currency != "EUR" || *price.CurrencySymbol != "€" || !price.IsNegotiable || *price.TypeLabel != "VB" {
+        t.Errorf("unexpected price %+v", price)
+    }
+
+    if position := advert.Position; *position.ZipCode != "10115" || *position.City != "Berlin" || position.Coordinate == nil || len(position.Locations) != 1 {
+        t.Errorf("unexpected position %+v", position)
+    }
+
+    if len(advert.Attributes) != 2 || advert.Attributes[0].KeySlug != "fahrraeder.art_s" || *advert.Attributes[0].ValueName != "Damen" {
+        t.Errorf("unexpected attributes %+v", advert.Attributes)
+    }
+
+    if options := advert.ShippingOptions; len(options) != 2 || options[0].Amount != nil || options[1].ID != "DHL_003" || options[1].Amount.Minor != 1649 || options[1].Amount.Currency != "EUR" {
+        t.Errorf("unexpected shipping options %+v", options)
+    }
+
+    if len(advert.Pictures) != 1 || *advert.Pictures[0].XXL != "https://img.example.com/1/XXL.jpg" {
+        t.Errorf("unexpected pictures %+v", advert.Pictures)
+    }
+
+    if *advert.Contact.Phone != "017612345678" {
+        t.Errorf("unexpected phone %s", *advert.Contact.Phone)
+    }
+
+    if created := advert.Timestamp.CreationTime; created == nil || !created.Equal(time.Date(2019, 4, 1, 8, 15, 30, 0, time.UTC)) {
+        t.Errorf("unexpected creation time %v", created)
+    }
+}
+
+func TestParseCategory(t *testing.T) {
+    category, report := deparser.ParseCategory(testutil.ReadFixture(t, "testdata/ads.xml"))
+    if report.IsFatal() || category == nil {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if len(category.Adverts) != 2 || category.Adverts[0].ID != 1 || category.Adverts[1].ID != 3 {
+        t.Errorf("expected adverts 1 and 3, got %+v", category.Adverts)
+    }
+
+    if category.Adverts[1].Price.IsNegotiable || *category.Adverts[1].Price.Type != "GIVE_AWAY" {
+        t.Errorf("unexpected price %+v", category.Adverts[1].Price)
+    }
+
+    if p := category.Pagination; p.CurrentPage != 0 || p.PageSize != 3 || p.EntrySize != 42 {
+        t.Errorf("unexpected pagination %+v", p)
+    }
+}
+
+func TestParseCategories(t *testing.T) {
+    categories, report := deparser.ParseCategories(testutil.ReadFixture(t, "testdata/categories.xml"))
+    if report.IsFatal() || len(categories) != 1 {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    root := categories[0]
+
+    if !root.IsRootCategory || len(root.Subcategories) != 2 {
+        t.Fatalf("unexpected root category %+v", root)
+    }
+
+    if root.ParentID != nil || root.ParentSlug != nil || len(report.Filter(parsers.SeverityMissingOptional)) < 1 {
+        t.Errorf("expected the missing parent of the root category to be nil and reported, got %+v", root)
+    }
+
+    hobby := root.Subcategories[0]
+    if hobby.ID != 185 || len(hobby.Subcategories) != 1 || hobby.Subcategories[0].Slug != "fahrraeder" || *hobby.Subcategories[0].ParentID != 185 {
+        t.Errorf("unexpected subcategory %+v", hobby)
+    }
+}
+
+func TestParseUnexpectedResponse(t *testing.T) {
+    testutil.RejectUnexpectedResponses(t, deparser.Parser{}, "testdata")
+}
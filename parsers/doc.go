@@ -0,0 +1,5 @@
+// Package parsers contains the parts of ECG Parser shared by all marketplaces, such as the API error document,
+// the category forest and the page control of advertisement lists.
+//
+// Country-specific parsers live in subpackages, e.g. `parsers/au`.
+package parsers
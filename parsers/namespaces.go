@@ -0,0 +1,11 @@
+package parsers
+
+// XML namespaces of the ECG API, declared with the prefixes used in its responses
+const (
+    NamespaceAd        = "http://www.ebayclassifiedsgroup.com/schema/ad/v1"        // prefix `ad`
+    NamespaceCategory  = "http://www.ebayclassifiedsgroup.com/schema/category/v1"  // prefix `cat`
+    NamespaceLocation  = "http://www.ebayclassifiedsgroup.com/schema/location/v1"  // prefix `loc`
+    NamespaceAttribute = "http://www.ebayclassifiedsgroup.com/schema/attribute/v1" // prefix `attr`
+    NamespaceTypes     = "http://www.ebayclassifiedsgroup.com/schema/types/v1"     // prefix `types`
+    NamespacePicture   = "http://www.ebayclassifiedsgroup.com/schema/picture/v1"   // prefix `pic`
+)
@@ -0,0 +1,152 @@
+package parsers
+
+import (
+    "regexp"
+    "strconv"
+    "strings"
+)
+
+// Mode decides which fields a parser requires
+type Mode int
+
+// Parsing modes
+const (
+    ModeLenient Mode = iota // replace missing or invalid fields with fallback values and keep going
+    ModeStrict              // require the fields declared as required by the marketplace parser
+    ModeCustom              // require the fields given by `RequireFields`
+)
+
+// DescriptionFormat is a set of representations of the description of an advertisement
+type DescriptionFormat uint
+
+// Representations of the description
+const (
+    DescriptionRawHTML  DescriptionFormat = 1 << iota // HTML as returned by the API, unsafe to render
+    DescriptionBase64                                 // plain text encoded in base64
+    DescriptionText                                   // plain UTF-8 text
+    DescriptionSafeHTML                               // HTML reduced to the allowed tags, safe to render
+
+    DescriptionAll = DescriptionRawHTML | DescriptionBase64 | DescriptionText | DescriptionSafeHTML
+)
+
+// Options configure how a parser treats missing or invalid fields and which representations it produces
+type Options struct {
+    Mode         Mode              // Parsing mode, lenient by default
+    Required     []string          // Paths of the fields required in custom mode, e.g. `ads/ad/price/amount`
+    Descriptions DescriptionFormat // Representations of the description, all of them if zero
+    AllowedTags  []string          // Tags kept in sanitised HTML, `utils.DefaultAllowedTags` if nil
+}
+
+// Option sets a parsing option
+type Option func(*Options)
+
+// Lenient substitutes fallback values for missing or invalid fields, which suits crawling
+func Lenient() Option {
+    return func(o *Options) {
+        o.Mode, o.Required = ModeLenient, nil
+    }
+}
+
+// Strict rejects advertisements missing any field the marketplace parser declares as required,
+// and rejects the response if a required field outside of an advertisement is missing
+func Strict() Option {
+    return func(o *Options) {
+        o.Mode, o.Required = ModeStrict, nil
+    }
+}
+
+// RequireFields rejects advertisements missing any of the given fields, and may be given several times.
+// Paths are matched without list indices, IDs and nesting, e.g. `ads/ad/pictures/thumbnail` matches
+// `ads/ad/pictures[0]/thumbnail` and `locations/location/id` matches `locations/location/3003435/location[1]/id`.
+func RequireFields(paths ...string) Option {
+    return func(o *Options) {
+        o.Mode, o.Required = ModeCustom, append(o.Required, paths...)
+    }
+}
+
+// Descriptions chooses the representations of the description, e.g. `DescriptionText | DescriptionSafeHTML`
+// to leave out the raw HTML
+func Descriptions(formats DescriptionFormat) Option {
+    return func(o *Options) {
+        o.Descriptions = formats
+    }
+}
+
+// AllowTags replaces the tags kept in sanitised HTML descriptions, e.g. `AllowTags("p", "br")`.
+// Attributes are stripped regardless, and scripts and styles are always removed.
+func AllowTags(tags ...string) Option {
+    return func(o *Options) {
+        o.AllowedTags = append([]string{}, tags...)
+    }
+}
+
+// Produces reports whether a representation of the description is chosen
+func (o Options) Produces(format DescriptionFormat) bool {
+    if o.Descriptions == 0 {
+        return DescriptionAll & format != 0
+    }
+
+    return o.Descriptions & format != 0
+}
+
+// NewOptions applies options to the lenient defaults
+func NewOptions(opts ...Option) Options {
+    var options Options
+
+    for _, opt := range opts {
+        if opt != nil {
+            opt(&options)
+        }
+    }
+
+    return options
+}
+
+// NewParseReport creates an empty report for a parser applying the given options,
+// where `required` are the paths the marketplace parser requires in strict mode
+func NewParseReport(required []string, opts ...Option) *ParseReport {
+    options := NewOptions(opts...)
+    report := &ParseReport{options: options}
+
+    switch options.Mode {
+    case ModeStrict:
+        report.required = fieldSet(required)
+    case ModeCustom:
+        report.required = fieldSet(options.Required)
+    }
+
+    return report
+}
+
+var listIndex = regexp.MustCompile(`\[\d+\]`)
+
+// fieldPath removes list indices, IDs and the nesting of elements of the same kind from a path,
+// e.g. `locations/location/3003435/location[1]/id` becomes `locations/location/id`
+func fieldPath(path string) string {
+    segments := strings.Split(listIndex.ReplaceAllString(strings.Trim(path, "/"), ""), "/")
+    kept := segments[:0]
+
+    for _, segment := range segments {
+        if _, err := strconv.ParseUint(segment, 10, 64); err == nil { // ID of an element
+            continue
+        }
+
+        if len(kept) > 0 && kept[len(kept) - 1] == segment { // element nested in one of the same kind
+            continue
+        }
+
+        kept = append(kept, segment)
+    }
+
+    return strings.Join(kept, "/")
+}
+
+func fieldSet(paths []string) map[string]bool {
+    set := make(map[string]bool, len(paths))
+
+    for _, path := range paths {
+        set[fieldPath(path)] = true
+    }
+
+    return set
+}
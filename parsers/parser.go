@@ -0,0 +1,97 @@
+package parsers
+
+import (
+    "github.com/beevik/etree"
+    "reflect"
+    "sort"
+    "sync"
+)
+
+// Parser parses the API responses of a marketplace into the models of that marketplace.
+// Each method returns the model and the report of parsing errors, the model being nil if the report is fatal.
+//
+// The model types differ between marketplaces, e.g. the Australian parser returns
+// `*aumodels.Advert`, `*aumodels.Category` and `[]aumodels.Categories`.
+type Parser interface {
+    ParseAdvert(doc *etree.Document) (interface{}, *ParseReport)      // a single advertisement
+    ParseAdvertList(doc *etree.Document) (interface{}, *ParseReport)  // a page of advertisements
+    ParseCategories(doc *etree.Document) (interface{}, *ParseReport)  // the category forest
+    ParseError(doc *etree.Document) *ErrorDocument                    // an `api-base-error` response
+}
+
+// LocationParser is implemented by parsers of marketplaces with a location hierarchy endpoint.
+// The Australian parser returns `[]aumodels.Location`.
+type LocationParser interface {
+    ParseLocations(doc *etree.Document) (interface{}, *ParseReport) // the location forest
+}
+
+// AttributeSchemaParser is implemented by parsers of marketplaces with an attribute metadata endpoint.
+// The Australian parser returns `*aumodels.AttributeSchema`.
+type AttributeSchemaParser interface {
+    ParseAttributeSchema(doc *etree.Document) (interface{}, *ParseReport) // the attribute metadata of a category
+}
+
+// Model adapts the result of a typed parsing function to the methods of `Parser`, turning a nil model
+// into an untyped nil so that it compares equal to nil as `interface{}`
+//
+//     func (p Parser) ParseAdvert(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+//         return parsers.Model(ParseAdvert(doc, p.Options...))
+//     }
+func Model(model interface{}, report *ParseReport) (interface{}, *ParseReport) {
+    if value := reflect.ValueOf(model); !value.IsValid() || isNil(value) {
+        return nil, report
+    }
+
+    return model, report
+}
+
+// isNil reports whether a value of a nillable kind is nil
+func isNil(value reflect.Value) bool {
+    switch value.Kind() {
+    case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+        return value.IsNil()
+    }
+
+    return false
+}
+
+var registry = struct {
+    sync.RWMutex
+    parsers map[string]Parser
+}{parsers: make(map[string]Parser)}
+
+// Register makes a parser available for a marketplace code, e.g. "au".
+// Country-specific parser packages register themselves when imported.
+func Register(marketplace string, parser Parser) {
+    if parser == nil {
+        panic("parsers: Register parser is nil")
+    }
+
+    registry.Lock()
+    defer registry.Unlock()
+
+    registry.parsers[marketplace] = parser
+}
+
+// Lookup returns the parser registered for a marketplace code
+func Lookup(marketplace string) (Parser, bool) {
+    registry.RLock()
+    defer registry.RUnlock()
+
+    parser, ok := registry.parsers[marketplace]
+    return parser, ok
+}
+
+// Marketplaces returns the sorted codes of all registered marketplaces
+func Marketplaces() []string {
+    registry.RLock()
+    defer registry.RUnlock()
+
+    marketplaces := make([]string, 0, len(registry.parsers))
+    for marketplace := range registry.parsers {
+        marketplaces = append(marketplaces, marketplace)
+    }
+    sort.Strings(marketplaces)
+
+    return marketplaces
+}
@@ -0,0 +1,254 @@
+package parsers
+
+import (
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Errors of responses that cannot be parsed at all
+var (
+    ErrEmptyResponse      = errors.New("empty API response")
+    ErrUnexpectedResponse = errors.New("unexpected API response")
+)
+
+// Severity is how much a parsing error affects the parsed model
+type Severity int
+
+// Severities in increasing order
+const (
+    SeverityMissingOptional Severity = iota // a field is missing and was left empty or replaced with a fallback
+    SeverityConversion                      // a field is present but its value could not be converted, a fallback was used
+    SeveritySkippedAdvert                   // an advertisement could not be built and was left out of the list
+    SeverityFatal                           // the response could not be parsed into a model
+)
+
+func (s Severity) String() string {
+    switch s {
+    case SeverityMissingOptional:
+        return "missing optional field"
+    case SeverityConversion:
+        return "conversion failed"
+    case SeveritySkippedAdvert:
+        return "skipped advert"
+    case SeverityFatal:
+        return "fatal"
+    }
+
+    return "severity " + strconv.Itoa(int(s))
+}
+
+// ParseError is a single problem encountered while parsing a response
+type ParseError struct {
+    Path     string   // Path of the field in the response, e.g. `ads/ad/price/amount`
+    AdvertID uint     // ID of the advertisement the field belongs to, zero if unknown or not part of an advertisement
+    Severity Severity // Effect of the error on the model
+    Value    string   // Raw value that could not be converted (optional)
+    Required bool     // Whether the field is required by the parsing options
+    Err      error    // Underlying cause, e.g. `utils.ErrElementMissing`, a `strconv` or a `money` error
+}
+
+func (e *ParseError) Error() string {
+    var msg strings.Builder
+
+    msg.WriteString("parsers: ")
+    msg.WriteString(e.Path)
+
+    if e.AdvertID > 0 {
+        fmt.Fprintf(&msg, " of advert %d", e.AdvertID)
+    }
+
+    fmt.Fprintf(&msg, ": %s", e.Severity)
+
+    if e.Required {
+        msg.WriteString(" of required field")
+    }
+
+    if e.Value != "" {
+        fmt.Fprintf(&msg, " for value %q", e.Value)
+    }
+
+    if e.Err != nil {
+        fmt.Fprintf(&msg, ": %v", e.Err)
+    }
+
+    return msg.String()
+}
+
+// Unwrap returns the underlying cause
+func (e *ParseError) Unwrap() error {
+    return e.Err
+}
+
+// ParseReport is the outcome of parsing a response, collecting all parsing errors.
+// The model is only usable if the report is not fatal.
+type ParseReport struct {
+    Errors []*ParseError // Parsing errors in the order they occurred
+
+    required map[string]bool // paths of required fields without list indices, see `NewParseReport`
+    options  Options         // options the report was created with
+}
+
+// Options returns the parsing options the report was created with by `NewParseReport`
+func (r *ParseReport) Options() Options {
+    return r.options
+}
+
+// Add appends a parsing error to the report
+func (r *ParseReport) Add(err *ParseError) {
+    r.Errors = append(r.Errors, err)
+}
+
+// Merge appends all parsing errors of another report
+func (r *ParseReport) Merge(other *ParseReport) {
+    if other != nil {
+        r.Errors = append(r.Errors, other.Errors...)
+    }
+}
+
+// Reporter returns a reporter adding errors of the given advertisement, or of the response itself if the ID is zero
+func (r *ParseReport) Reporter(advertID uint) Reporter {
+    return Reporter{report: r, AdvertID: advertID}
+}
+
+// Len returns the number of parsing errors
+func (r *ParseReport) Len() int {
+    if r == nil {
+        return 0
+    }
+
+    return len(r.Errors)
+}
+
+// IsFatal reports whether the response could not be parsed into a model
+func (r *ParseReport) IsFatal() bool {
+    return r.Err() != nil
+}
+
+// Err returns the first fatal parsing error, or nil if the model is usable
+func (r *ParseReport) Err() error {
+    if fatal := r.Filter(SeverityFatal); len(fatal) > 0 {
+        return fatal[0]
+    }
+
+    return nil
+}
+
+// Skipped reports whether an advertisement was left out
+func (r *ParseReport) Skipped(advertID uint) bool {
+    for _, err := range r.Filter(SeveritySkippedAdvert) {
+        if err.AdvertID == advertID {
+            return true
+        }
+    }
+
+    return false
+}
+
+// Filter returns the parsing errors of a severity
+func (r *ParseReport) Filter(severity Severity) []*ParseError {
+    var filtered []*ParseError
+
+    if r != nil {
+        for _, err := range r.Errors {
+            if err.Severity == severity {
+                filtered = append(filtered, err)
+            }
+        }
+    }
+
+    return filtered
+}
+
+// Warnings returns the non-fatal parsing errors
+func (r *ParseReport) Warnings() []error {
+    var warnings []error
+
+    if r != nil {
+        for _, err := range r.Errors {
+            if err.Severity < SeverityFatal {
+                warnings = append(warnings, err)
+            }
+        }
+    }
+
+    return warnings
+}
+
+// String returns all parsing errors separated by semicolons
+func (r *ParseReport) String() string {
+    if r.Len() < 1 {
+        return "no parsing errors"
+    }
+
+    messages := make([]string, len(r.Errors))
+    for i, err := range r.Errors {
+        messages[i] = err.Error()
+    }
+
+    return strings.Join(messages, "; ")
+}
+
+// Reporter adds parsing errors to a report on behalf of an advertisement, and implements `utils.ErrorReporter`
+type Reporter struct {
+    AdvertID uint // ID of the advertisement, zero if not part of an advertisement
+
+    report *ParseReport
+}
+
+// Report adds an error of a field replaced with a fallback value, which is either missing or failed to convert.
+// If the field is required, the advertisement is skipped instead, or the response rejected outside of an advertisement.
+func (r Reporter) Report(path string, err error) {
+    severity := SeverityConversion
+    if errors.Is(err, u.ErrElementMissing) || errors.Is(err, u.ErrAttributeMissing) {
+        severity = SeverityMissingOptional
+    }
+
+    if r.report.required[fieldPath(path)] {
+        if severity = SeverityFatal; r.AdvertID > 0 {
+            severity = SeveritySkippedAdvert
+        }
+    }
+
+    r.add(path, severity, err)
+}
+
+// Skip adds an error that caused an advertisement to be left out
+func (r Reporter) Skip(path string, err error) {
+    r.add(path, SeveritySkippedAdvert, err)
+}
+
+// Fatal adds an error that caused the entire response to be rejected
+func (r Reporter) Fatal(path string, err error) {
+    r.add(path, SeverityFatal, err)
+}
+
+func (r Reporter) add(path string, severity Severity, err error) {
+    var value string
+
+    var numErr *strconv.NumError
+    var moneyErr *money.ParseError
+    var timeErr *time.ParseError
+
+    if errors.As(err, &numErr) {
+        value = numErr.Num
+    } else if errors.As(err, &moneyErr) {
+        value = moneyErr.Text
+    } else if errors.As(err, &timeErr) {
+        value = timeErr.Value
+    }
+
+    r.report.Add(&ParseError{
+        Path:     path,
+        AdvertID: r.AdvertID,
+        Severity: severity,
+        Value:    value,
+        Required: r.report.required[fieldPath(path)],
+        Err:      err,
+    })
+}
+
@@ -0,0 +1,142 @@
+package parsers_test
+
+import (
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api/internal/testutil"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    "github.com/GreenVine/ebay-classifieds-api/parsers/de"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "strconv"
+    "testing"
+)
+
+func TestParseReport(t *testing.T) {
+    report := parsers.NewParseReport([]string{"ads/ad/title", "category/id"}, parsers.Strict())
+
+    if report.Len() != 0 || report.IsFatal() || report.String() != "no parsing errors" {
+        t.Fatalf("expected empty report, got %v", report)
+    }
+
+    _, numErr := strconv.ParseUint("twelve", 10, 64)
+
+    report.Reporter(7).Report("ads/ad[3]/description", u.ErrElementMissing)
+    report.Reporter(7).Report("ads/ad/price/amount", numErr)
+    report.Reporter(8).Report("ads/ad[1]/title", u.ErrElementMissing)
+
+    if missing := report.Filter(parsers.SeverityMissingOptional); len(missing) != 1 || missing[0].AdvertID != 7 || missing[0].Required {
+        t.Errorf("expected a missing optional field of advert 7, got %v", missing)
+    }
+
+    if conversions := report.Filter(parsers.SeverityConversion); len(conversions) != 1 || conversions[0].Value != "twelve" || !errors.Is(conversions[0], strconv.ErrSyntax) {
+        t.Errorf("expected a conversion error with its raw value, got %v", conversions)
+    }
+
+    if !report.Skipped(8) || report.Skipped(7) || report.IsFatal() {
+        t.Errorf("expected only advert 8 to be skipped for its required title, got %v", report)
+    }
+
+    report.Reporter(0).Report("category/id", u.ErrAttributeMissing)
+
+    if err := report.Err(); !report.IsFatal() || !errors.Is(err, u.ErrAttributeMissing) || err.(*parsers.ParseError).Path != "category/id" {
+        t.Errorf("expected a required field outside of an advert to be fatal, got %v", err)
+    }
+
+    if warnings := report.Warnings(); len(warnings) != 3 {
+        t.Errorf("expected 3 non-fatal errors, got %v", warnings)
+    }
+
+    merged := &parsers.ParseReport{}
+    merged.Merge(report)
+    merged.Merge(nil)
+
+    if merged.Len() != report.Len() || !merged.IsFatal() {
+        t.Errorf("expected merged report to hold all errors, got %v", merged)
+    }
+
+    if severity := parsers.SeveritySkippedAdvert.String(); severity != "skipped advert" {
+        t.Errorf("unexpected severity %q", severity)
+    }
+}
+
+func TestParseReportLenient(t *testing.T) {
+    report := parsers.NewParseReport([]string{"ads/ad/title"})
+    report.Reporter(8).Report("ads/ad/title", u.ErrElementMissing)
+
+    if report.Skipped(8) || len(report.Filter(parsers.SeverityMissingOptional)) != 1 {
+        t.Errorf("expected required fields to be optional in lenient mode, got %v", report)
+    }
+}
+
+func TestParseReportNestedPaths(t *testing.T) {
+    report := parsers.NewParseReport(nil, parsers.RequireFields("categories/category/name", "ads/ad/pictures/thumbnail"))
+    reporter := report.Reporter(0)
+
+    reporter.Report("categories/category/18320/category[1]/name", u.ErrElementMissing)
+    reporter.Report("categories/category/18320/slug", u.ErrElementMissing)
+
+    if fatal := report.Filter(parsers.SeverityFatal); len(fatal) != 1 || fatal[0].Path != "categories/category/18320/category[1]/name" {
+        t.Errorf("expected the name of the nested category to be required, got %v", report)
+    }
+
+    report.Reporter(3).Report("ads/ad/pictures[2]/thumbnail", u.ErrAttributeMissing)
+
+    if !report.Skipped(3) {
+        t.Errorf("expected the advert without thumbnail to be skipped, got %v", report)
+    }
+}
+
+func TestParseReportAU(t *testing.T) {
+    doc := testutil.ReadFixture(t, "au/testdata/ads.xml")
+    doc.FindElement("//ad:ad[@id='1']/ad:price/types:amount").SetText("fifty")
+
+    _, report := auparser.ParseCategory(doc)
+    if report.IsFatal() {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    conversions := report.Filter(parsers.SeverityConversion)
+    if len(conversions) != 1 || conversions[0].AdvertID != 1 || conversions[0].Path != "ads/ad/price/amount" || conversions[0].Value != "fifty" {
+        t.Errorf("expected a conversion error of the amount of advert 1, got %v", conversions)
+    }
+
+    category, report := auparser.ParseCategory(doc, parsers.Strict())
+    if report.IsFatal() || len(category.Adverts) != 0 {
+        t.Fatalf("expected both adverts to be skipped in strict mode, got %v", report)
+    }
+
+    for _, id := range []uint{1, 2} {
+        if !report.Skipped(id) {
+            t.Errorf("expected advert %d to be skipped, got %v", id, report)
+        }
+    }
+
+    if skipped := report.Filter(parsers.SeveritySkippedAdvert); skipped[0].Value != "fifty" || !skipped[0].Required {
+        t.Errorf("expected the invalid required amount of advert 1 to be reported, got %v", skipped[0])
+    }
+}
+
+func TestParseReportDE(t *testing.T) {
+    doc := testutil.ReadFixture(t, "de/testdata/ads.xml")
+    doc.FindElement("//ad:ad[@id='1']/ad:price/types:amount").SetText("vierzig")
+
+    _, report := deparser.ParseCategory(doc)
+    if report.IsFatal() {
+        t.Fatalf("unexpected fatal error: %v", report)
+    }
+
+    if skipped := report.Filter(parsers.SeveritySkippedAdvert); len(skipped) != 1 || skipped[0].Path != "ads/ad/id" || !errors.Is(skipped[0], u.ErrAttributeMissing) {
+        t.Errorf("expected the advert without ID to be skipped, got %v", skipped)
+    }
+
+    conversions := report.Filter(parsers.SeverityConversion)
+    if len(conversions) != 1 || conversions[0].AdvertID != 1 || conversions[0].Path != "ads/ad/price/amount" || conversions[0].Value != "vierzig" {
+        t.Errorf("expected a conversion error of the amount of advert 1, got %v", conversions)
+    }
+
+    for _, err := range report.Filter(parsers.SeverityMissingOptional) {
+        if err.AdvertID != 1 && err.AdvertID != 3 {
+            t.Errorf("expected missing fields to be attributed to an advert, got %v", err)
+        }
+    }
+}
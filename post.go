@@ -0,0 +1,131 @@
+package ecg
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    auparser "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    aumodels "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+)
+
+// Codes of the field errors of drafts rejected before they are sent
+const (
+    FieldErrorRequired         = "REQUIRED"
+    FieldErrorNotAllowed       = "NOT_ALLOWED"
+    FieldErrorInvalidValue     = "INVALID_VALUE"
+    FieldErrorUnknownAttribute = "UNKNOWN_ATTRIBUTE"
+)
+
+// CreateAdvert posts a new advertisement on behalf of the authenticated user and returns the created advertisement.
+// The draft needs a title, a category and a price type, and its attributes are validated against the attribute
+// metadata of its category first. An invalid draft is not sent and yields a `*ValidationError` listing every
+// offending field, whereas a draft rejected by the API yields an `*APIError` with the field errors of the API.
+//
+// The agent needs the user authentication credential within the scope of posting, and the marketplace parser
+// must parse attribute metadata. Fields assigned by the API, such as the ID, status and timestamps, must be empty.
+func (agent Agent) CreateAdvert(ctx context.Context, draft *aumodels.Advert) (*aumodels.Advert, *parsers.ParseReport, error) {
+    if !agent.hasECGAuthentication() || agent.ECGAuthentication.AuthenticateUser == "" {
+        return nil, nil, ErrNoUserCredential
+    }
+
+    if !agent.ECGAuthentication.scope("POST", createAdvertPath).Has(AuthScopeUser) {
+        return nil, nil, fmt.Errorf("%w: not in the scope of POST %s", ErrNoUserCredential, createAdvertPath)
+    }
+
+    if err := agent.validateDraft(ctx, draft); err != nil {
+        return nil, nil, err
+    }
+
+    body, err := auparser.EncodeAdvert(draft)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    doc, err := agent.RequestCreateAdvert(ctx, body)
+    if err != nil {
+        return nil, nil, err
+    }
+
+    if doc == nil {
+        return nil, nil, fmt.Errorf("%w: no advert in the response", ErrDecode)
+    }
+
+    var advert *aumodels.Advert
+
+    report, err := typed(agent.parse(doc, parsers.Parser.ParseAdvert))(&advert)
+    return advert, report, err
+}
+
+// validateDraft checks the fields of a draft and its attributes against the schema of its category
+func (agent Agent) validateDraft(ctx context.Context, draft *aumodels.Advert) error {
+    if draft == nil {
+        return &ValidationError{Fields: []parsers.FieldError{{Field: "ad", Code: FieldErrorRequired, Message: "draft is missing"}}}
+    }
+
+    var fields []parsers.FieldError
+
+    for _, assigned := range []struct {
+        field string
+        set   bool
+    }{
+        {"id", draft.ID > 0},
+        {"status", draft.Status != nil && *draft.Status != ""},
+        {"user_id", draft.UserID != nil && *draft.UserID > 0},
+        {"timestamp.creation_time", draft.Timestamp.CreationTime != nil},
+        {"timestamp.modification_time", draft.Timestamp.ModificationTime != nil},
+        {"timestamp.start_time", draft.Timestamp.StartTime != nil},
+        {"timestamp.end_time", draft.Timestamp.EndTime != nil},
+    } {
+        if assigned.set {
+            fields = append(fields, parsers.FieldError{Field: assigned.field, Code: FieldErrorNotAllowed, Message: assigned.field + " is assigned by the API"})
+        }
+    }
+
+    if draft.Title == "" {
+        fields = append(fields, parsers.FieldError{Field: "title", Code: FieldErrorRequired, Message: "title is missing"})
+    }
+
+    if draft.Price == nil || draft.Price.Type == nil || *draft.Price.Type == "" {
+        fields = append(fields, parsers.FieldError{Field: "price.type", Code: FieldErrorRequired, Message: "price type is missing"})
+    }
+
+    if draft.Category == nil || draft.Category.ID == 0 {
+        fields = append(fields, parsers.FieldError{Field: "category.id", Code: FieldErrorRequired, Message: "category is missing"})
+        return &ValidationError{Fields: fields}
+    }
+
+    schema, _, err := agent.GetAttributeSchema(ctx, draft.Category.ID)
+    if err != nil {
+        return err
+    }
+
+    attrErr := schema.Validate(draft.Attributes)
+
+    var invalid *aumodels.ValidationError
+    if errors.As(attrErr, &invalid) {
+        for _, e := range invalid.Errors {
+            fields = append(fields, attributeFieldError(e))
+        }
+    }
+
+    if len(fields) > 0 {
+        return &ValidationError{Fields: fields, Err: attrErr}
+    }
+
+    return nil
+}
+
+// attributeFieldError converts an attribute that does not conform to the schema into a field error
+func attributeFieldError(err *aumodels.AttributeError) parsers.FieldError {
+    code := FieldErrorInvalidValue
+
+    switch {
+    case errors.Is(err, aumodels.ErrAttributeRequired):
+        code = FieldErrorRequired
+    case errors.Is(err, aumodels.ErrAttributeUnknown):
+        code = FieldErrorUnknownAttribute
+    }
+
+    return parsers.FieldError{Field: "attributes." + err.Name, Code: code, Message: err.Error()}
+}
@@ -0,0 +1,186 @@
+package ecg_test
+
+import (
+    "context"
+    "errors"
+    "github.com/GreenVine/ebay-classifieds-api"
+    "github.com/GreenVine/ebay-classifieds-api/money"
+    aumodels "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+    "time"
+)
+
+const attributesXML = `<?xml version="1.0" encoding="UTF-8"?>
+<attr:attributes xmlns:attr="http://www.ebayclassifiedsgroup.com/schema/attribute/v1" category-id="18320">
+    <attr:attribute name="cars.carmake_s" localized-label="Make" type="ENUM" write="required">
+        <attr:supported-value localized-label="Toyota">toyota</attr:supported-value>
+    </attr:attribute>
+    <attr:attribute name="cars.caryear_i" localized-label="Year" type="INTEGER" write="optional"/>
+</attr:attributes>`
+
+func newDraft() *aumodels.Advert {
+    priceType, currency, carMake, year := "FIXED", "AUD", "toyota", "2015"
+    amount := money.New(1499000, currency)
+
+    return &aumodels.Advert{
+        Title:    "2015 Toyota Corolla",
+        Price:    &aumodels.AdvertPrice{Type: &priceType, Amount: &amount, Currency: &currency},
+        Category: &aumodels.AdvertCategory{ID: 18320},
+        Attributes: []aumodels.AdvertAttribute{
+            {KeySlug: "cars.carmake_s", ValueSlug: &carMake},
+            {KeySlug: "cars.caryear_i", ValueSlug: &year},
+        },
+    }
+}
+
+func TestAgentCreateAdvert(t *testing.T) {
+    posted := 0
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        switch {
+        case r.Method == "GET" && r.URL.Path == "/categories/18320/attributes":
+            w.Write([]byte(attributesXML))
+        case r.Method == "POST" && r.URL.Path == "/ads":
+            posted++
+
+            if r.Header.Get(ecg.HeaderAuthenticateUser) != "user-token" {
+                t.Errorf("expected user authentication, got headers %v", r.Header)
+            }
+
+            doc := etree.NewDocument()
+            if _, err := doc.ReadFrom(r.Body); err != nil || doc.FindElement("/ad:ad/attr:attributes/attr:attribute[@name='cars.carmake_s']/attr:value") == nil {
+                t.Errorf("unexpected body: %v", err)
+            }
+
+            doc.Root().CreateAttr("id", "123456")
+            doc.WriteTo(w)
+        default:
+            w.WriteHeader(http.StatusNotFound)
+        }
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL, ECGAuthentication: &ecg.Authentication{AuthenticateUser: "user-token"}}
+
+    advert, _, err := agent.CreateAdvert(context.Background(), newDraft())
+    if err != nil || advert == nil || advert.ID != 123456 || advert.Title != "2015 Toyota Corolla" || advert.Price.Amount.Minor != 1499000 {
+        t.Fatalf("unexpected advert %+v: %v", advert, err)
+    }
+
+    draft := newDraft()
+    draft.Title = ""
+    draft.Attributes = draft.Attributes[1:]
+    year := "soon"
+    draft.Attributes[0].ValueSlug = &year
+
+    _, _, err = agent.CreateAdvert(context.Background(), draft)
+
+    var invalid *ecg.ValidationError
+    if !errors.Is(err, ecg.ErrInvalidDraft) || !errors.As(err, &invalid) || !errors.Is(err, aumodels.ErrAttributeRequired) {
+        t.Fatalf("expected validation error, got %v", err)
+    }
+
+    if fields := invalid.FieldErrors(); len(fields) != 3 || fields[0].Field != "title" || fields[1].Field != "attributes.cars.carmake_s" || fields[1].Code != ecg.FieldErrorRequired || fields[2].Code != ecg.FieldErrorInvalidValue {
+        t.Errorf("unexpected field errors %v", fields)
+    }
+
+    if posted != 1 {
+        t.Errorf("expected the invalid draft not to be posted, got %d posts", posted)
+    }
+
+    agent.ECGAuthentication = nil
+
+    if _, _, err := agent.CreateAdvert(context.Background(), newDraft()); !errors.Is(err, ecg.ErrNoUserCredential) {
+        t.Errorf("expected user credential to be required, got %v", err)
+    }
+}
+
+func TestAgentCreateAdvertRejected(t *testing.T) {
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == "GET" {
+            w.Write([]byte(attributesXML))
+            return
+        }
+
+        w.WriteHeader(http.StatusBadRequest)
+        w.Write([]byte(apiErrorXML))
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL, ECGAuthentication: &ecg.Authentication{AuthenticateUser: "user-token"}}
+
+    _, _, err := agent.CreateAdvert(context.Background(), newDraft())
+
+    var apiErr *ecg.APIError
+    if !errors.As(err, &apiErr) || len(apiErr.FieldErrors()) != 2 {
+        t.Errorf("expected the field errors of the API, got %v", err)
+    }
+}
+
+func TestAgentCreateAdvertPreconditions(t *testing.T) {
+    posted := 0
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Method == "GET" {
+            w.Write([]byte(attributesXML))
+            return
+        }
+
+        posted++
+        w.WriteHeader(http.StatusBadRequest)
+    }))
+    defer server.Close()
+
+    deviceOnly := func(method string, path string) ecg.AuthScope {
+        return ecg.AuthScopeDevice
+    }
+
+    agent := ecg.Agent{Endpoint: server.URL, ECGAuthentication: &ecg.Authentication{AuthenticateUser: "user-token", Scope: deviceOnly}}
+
+    if _, _, err := agent.CreateAdvert(context.Background(), newDraft()); !errors.Is(err, ecg.ErrNoUserCredential) {
+        t.Errorf("expected the user scope to be required, got %v", err)
+    }
+
+    agent.ECGAuthentication.Scope = nil
+
+    status, userID, created := "ACTIVE", uint(42), time.Now()
+
+    draft := newDraft()
+    draft.ID, draft.Status, draft.UserID = 123456, &status, &userID
+    draft.Timestamp.CreationTime = &created
+
+    _, _, err := agent.CreateAdvert(context.Background(), draft)
+
+    var invalid *ecg.ValidationError
+    if !errors.As(err, &invalid) {
+        t.Fatalf("expected validation error, got %v", err)
+    }
+
+    var rejected []string
+    for _, field := range invalid.FieldErrors() {
+        if field.Code == ecg.FieldErrorNotAllowed {
+            rejected = append(rejected, field.Field)
+        }
+    }
+
+    if !reflect.DeepEqual(rejected, []string{"id", "status", "user_id", "timestamp.creation_time"}) {
+        t.Errorf("expected the fields assigned by the API to be rejected, got %v", invalid.FieldErrors())
+    }
+
+    if posted != 0 {
+        t.Errorf("expected nothing to be posted, got %d posts", posted)
+    }
+
+    empty, zero := "", uint(0)
+
+    draft = newDraft()
+    draft.Status, draft.UserID = &empty, &zero // as left by the parser for missing fields
+
+    if _, _, err := agent.CreateAdvert(context.Background(), draft); errors.Is(err, ecg.ErrInvalidDraft) || posted != 1 {
+        t.Errorf("expected empty server fields to be accepted and the draft posted, got %v", err)
+    }
+}
@@ -0,0 +1,265 @@
+package ecg
+
+import (
+    "context"
+    "strings"
+    "sync"
+    "time"
+)
+
+// EndpointClass groups endpoints sharing the same rate limit
+type EndpointClass string
+
+// Endpoint classes recognised by `ClassifyEndpoint`
+const (
+    EndpointClassDefault  EndpointClass = ""
+    EndpointClassSearch   EndpointClass = "search"   // advertisement lists and searches
+    EndpointClassAdvert   EndpointClass = "advert"   // single advertisements
+    EndpointClassCategory EndpointClass = "category" // category trees
+    EndpointClassWrite    EndpointClass = "write"    // any request modifying data
+)
+
+// ClassifyEndpoint is the default classification of requests into endpoint classes
+func ClassifyEndpoint(method string, path string) EndpointClass {
+    if method != "" && method != "GET" && method != "HEAD" {
+        return EndpointClassWrite
+    }
+
+    if i := strings.IndexAny(path, "?#"); i >= 0 { // ignore query and fragment
+        path = path[:i]
+    }
+
+    segments := strings.Split(strings.Trim(path, "/"), "/")
+
+    switch {
+    case segments[0] == "categories":
+        return EndpointClassCategory
+    case segments[0] == "ads" && len(segments) == 1:
+        return EndpointClassSearch
+    case segments[0] == "ads":
+        return EndpointClassAdvert
+    }
+
+    return EndpointClassDefault
+}
+
+// Limit is the request quota of a rate limiter or an endpoint class
+type Limit struct {
+    Rate        float64 // sustained requests per second, zero for no rate limit
+    Burst       uint    // requests that can be sent at once before the rate applies, at least one
+    MaxInFlight uint    // concurrent requests, zero for no concurrency cap
+}
+
+// LimiterStats are the metrics of requests passing a rate limiter
+type LimiterStats struct {
+    Requests  uint64        // requests admitted
+    Delayed   uint64        // requests that had to wait
+    Cancelled uint64        // requests whose context ended while waiting
+    TotalWait time.Duration // time spent waiting in total
+    MaxWait   time.Duration // longest time a request waited
+}
+
+// RateLimiter is a token-bucket rate limiter with a cap on requests in flight, safe for concurrent use.
+// Every request is subject to the default limit and additionally to the limit of its endpoint class.
+// A single limiter can be shared by several agents to enforce a common quota.
+type RateLimiter struct {
+    Classify func(method string, path string) EndpointClass // overrides `ClassifyEndpoint` (optional)
+
+    global  *limiter
+    classes map[EndpointClass]*limiter
+
+    mu    sync.Mutex
+    stats map[EndpointClass]*LimiterStats
+}
+
+// limiter is a single token bucket and semaphore
+type limiter struct {
+    limit Limit
+
+    mu     sync.Mutex
+    tokens float64
+    last   time.Time
+
+    slots chan struct{}
+}
+
+// NewRateLimiter creates a rate limiter with a default limit and optional limits per endpoint class
+func NewRateLimiter(defaults Limit, classes map[EndpointClass]Limit) *RateLimiter {
+    rl := &RateLimiter{
+        global:  newLimiter(defaults),
+        classes: make(map[EndpointClass]*limiter),
+        stats:   make(map[EndpointClass]*LimiterStats),
+    }
+
+    for class, limit := range classes {
+        rl.classes[class] = newLimiter(limit)
+    }
+
+    return rl
+}
+
+func newLimiter(limit Limit) *limiter {
+    if limit.Burst < 1 {
+        limit.Burst = 1
+    }
+
+    l := &limiter{
+        limit:  limit,
+        tokens: float64(limit.Burst),
+        last:   time.Now(),
+    }
+
+    if limit.MaxInFlight > 0 {
+        l.slots = make(chan struct{}, limit.MaxInFlight)
+    }
+
+    return l
+}
+
+// Wait blocks until a request of the given method and path may be sent, or the context ends.
+// The returned function must be called once the request has completed to release its slot.
+func (rl *RateLimiter) Wait(ctx context.Context, method string, path string) (func(), error) {
+    classify := rl.Classify
+    if classify == nil {
+        classify = ClassifyEndpoint
+    }
+
+    class := classify(method, path)
+    limiters := []*limiter{rl.global}
+
+    if l, ok := rl.classes[class]; ok {
+        limiters = append(limiters, l)
+    }
+
+    start := time.Now()
+    var releases []func()
+
+    release := func() {
+        for i := len(releases) - 1; i >= 0; i-- {
+            releases[i]()
+        }
+    }
+
+    for _, l := range limiters {
+        r, err := l.wait(ctx)
+        if err != nil {
+            release()
+            rl.record(class, time.Since(start), true)
+            return nil, err
+        }
+
+        releases = append(releases, r)
+    }
+
+    rl.record(class, time.Since(start), false)
+
+    return release, nil
+}
+
+// Stats returns a snapshot of the metrics per endpoint class
+func (rl *RateLimiter) Stats() map[EndpointClass]LimiterStats {
+    rl.mu.Lock()
+    defer rl.mu.Unlock()
+
+    snapshot := make(map[EndpointClass]LimiterStats, len(rl.stats))
+    for class, stats := range rl.stats {
+        snapshot[class] = *stats
+    }
+
+    return snapshot
+}
+
+func (rl *RateLimiter) record(class EndpointClass, wait time.Duration, cancelled bool) {
+    rl.mu.Lock()
+    defer rl.mu.Unlock()
+
+    stats, ok := rl.stats[class]
+    if !ok {
+        stats = &LimiterStats{}
+        rl.stats[class] = stats
+    }
+
+    if cancelled {
+        stats.Cancelled++
+    } else {
+        stats.Requests++
+    }
+
+    if wait >= time.Millisecond { // ignore scheduling noise
+        stats.Delayed++
+        stats.TotalWait += wait
+
+        if wait > stats.MaxWait {
+            stats.MaxWait = wait
+        }
+    }
+}
+
+// wait acquires a slot and a token, returning the function releasing the slot
+func (l *limiter) wait(ctx context.Context) (func(), error) {
+    if l.slots != nil {
+        select {
+        case l.slots <- struct{}{}:
+        case <-ctx.Done():
+            return nil, ctx.Err()
+        }
+    }
+
+    release := func() {
+        if l.slots != nil {
+            <-l.slots
+        }
+    }
+
+    if delay := l.reserve(); delay > 0 {
+        timer := time.NewTimer(delay)
+
+        select {
+        case <-timer.C:
+        case <-ctx.Done():
+            timer.Stop()
+            l.cancel()
+            release()
+            return nil, ctx.Err()
+        }
+    }
+
+    return release, nil
+}
+
+// reserve takes a token from the bucket and returns how long to wait until the token is available
+func (l *limiter) reserve() time.Duration {
+    if l.limit.Rate <= 0 {
+        return 0
+    }
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    now := time.Now()
+    l.tokens += now.Sub(l.last).Seconds() * l.limit.Rate
+    l.last = now
+
+    if burst := float64(l.limit.Burst); l.tokens > burst {
+        l.tokens = burst
+    }
+
+    l.tokens-- // may go negative, queueing the request behind earlier reservations
+
+    if l.tokens >= 0 {
+        return 0
+    }
+
+    return time.Duration(-l.tokens / l.limit.Rate * float64(time.Second))
+}
+
+// cancel returns a reserved token to the bucket
+func (l *limiter) cancel() {
+    if l.limit.Rate <= 0 {
+        return
+    }
+
+    l.mu.Lock()
+    l.tokens++
+    l.mu.Unlock()
+}
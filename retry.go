@@ -0,0 +1,135 @@
+package ecg
+
+import (
+    "math"
+    "math/rand"
+    "net/http"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// RetryPolicy controls how failed requests are retried with exponential backoff.
+// A nil policy on the agent disables retries.
+type RetryPolicy struct {
+    MaxAttempts     uint          // total number of attempts including the first one
+    BaseDelay       time.Duration // delay before the first retry, doubled on every subsequent retry
+    MaxDelay        time.Duration // upper bound of the backoff delay
+    Jitter          float64       // fraction (0 to 1) of each delay that is randomised, zero disables jitter
+    MaxRetryAfter   time.Duration // longest `Retry-After` the agent waits for, zero to never give up on it
+    StatusCodes     []uint        // HTTP status codes that are retried
+    TransportErrors bool          // whether failures without a response (e.g. connection reset) are retried
+    Methods         []string      // HTTP methods that are retried, only idempotent methods by default
+}
+
+// DefaultRetryPolicy is a sensible starting point for a retry policy
+var DefaultRetryPolicy = RetryPolicy{
+    MaxAttempts:     3,
+    BaseDelay:       200 * time.Millisecond,
+    MaxDelay:        10 * time.Second,
+    Jitter:          0.5,
+    MaxRetryAfter:   30 * time.Second,
+    StatusCodes:     []uint{429, 502, 503, 504},
+    TransportErrors: true,
+    Methods:         []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"},
+}
+
+// attemptOutcome describes the result of a single attempt as seen by the retry policy
+type attemptOutcome struct {
+    statusCode   uint          // HTTP status code, zero if no response was received
+    transportErr bool          // request failed before a response was received
+    retryAfter   time.Duration // delay requested by the `Retry-After` header
+}
+
+var jitterSource = struct {
+    sync.Mutex
+    *rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// retries reports whether a failed attempt of the method is retried
+func (policy RetryPolicy) retries(method string, outcome attemptOutcome) bool {
+    methods := policy.Methods
+    if methods == nil {
+        methods = DefaultRetryPolicy.Methods
+    }
+
+    isRetryableMethod := false
+    for _, m := range methods {
+        if strings.EqualFold(m, method) {
+            isRetryableMethod = true
+            break
+        }
+    }
+
+    if !isRetryableMethod {
+        return false
+    }
+
+    if outcome.transportErr {
+        return policy.TransportErrors
+    }
+
+    statusCodes := policy.StatusCodes
+    if statusCodes == nil {
+        statusCodes = DefaultRetryPolicy.StatusCodes
+    }
+
+    for _, code := range statusCodes {
+        if code == outcome.statusCode {
+            return true
+        }
+    }
+
+    return false
+}
+
+// delay computes the delay before the next attempt after `attempt` attempts were made.
+// It returns false if the requested `Retry-After` exceeds `MaxRetryAfter`.
+func (policy RetryPolicy) delay(attempt uint, outcome attemptOutcome) (time.Duration, bool) {
+    if policy.MaxRetryAfter > 0 && outcome.retryAfter > policy.MaxRetryAfter {
+        return 0, false
+    }
+
+    base, max := policy.BaseDelay, policy.MaxDelay
+    if base <= 0 {
+        base = DefaultRetryPolicy.BaseDelay
+    }
+    if max <= 0 {
+        max = DefaultRetryPolicy.MaxDelay
+    }
+
+    backoff := time.Duration(math.Min(float64(base) * math.Pow(2, float64(attempt - 1)), float64(max)))
+
+    if jitter := math.Min(math.Max(policy.Jitter, 0), 1); jitter > 0 {
+        jitterSource.Lock()
+        backoff -= time.Duration(jitter * jitterSource.Float64() * float64(backoff))
+        jitterSource.Unlock()
+    }
+
+    if outcome.retryAfter > backoff {
+        return outcome.retryAfter, true
+    }
+
+    return backoff, true
+}
+
+// parseRetryAfter parses the `Retry-After` header, given either in seconds or as a HTTP date
+func parseRetryAfter(header http.Header) time.Duration {
+    value := strings.TrimSpace(header.Get("Retry-After"))
+    if value == "" {
+        return 0
+    }
+
+    if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+        return time.Duration(seconds) * time.Second
+    }
+
+    if date, err := http.ParseTime(value); err == nil {
+        if delay := time.Until(date); delay > 0 {
+            return delay
+        }
+    }
+
+    return 0
+}
@@ -0,0 +1,278 @@
+package ecg
+
+import (
+    "context"
+    "fmt"
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "github.com/beevik/etree"
+    "net/url"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// AdType is the type of an advertisement
+type AdType string
+
+// Advertisement types
+const (
+    AdTypeOffered AdType = "OFFERED" // item or service offered
+    AdTypeWanted  AdType = "WANTED"  // item or service wanted
+)
+
+// PosterType is the type of the advertiser
+type PosterType string
+
+// Advertiser types
+const (
+    PosterTypePrivate    PosterType = "PRIVATE"
+    PosterTypeCommercial PosterType = "COMMERCIAL"
+)
+
+// SortType is the order of search results
+type SortType string
+
+// Search result orders
+const (
+    SortDateDescending    SortType = "DATE_DESCENDING"
+    SortDateAscending     SortType = "DATE_ASCENDING"
+    SortPriceAscending    SortType = "PRICE_ASCENDING"
+    SortPriceDescending   SortType = "PRICE_DESCENDING"
+    SortDistanceAscending SortType = "DISTANCE_ASCENDING"
+)
+
+// MaxPageSize is the largest page size accepted by the API
+const MaxPageSize = 200
+
+// SearchQuery is the set of parameters of an advertisement search. Zero values are left out of the query.
+type SearchQuery struct {
+    Keyword    string            // free text search
+    CategoryID uint              // category to search in
+    LocationID uint              // location to search around
+    Distance   uint              // search radius around the location in kilometres, requires `LocationID`
+    MinPrice   uint              // lowest price in whole currency units
+    MaxPrice   uint              // highest price in whole currency units
+    AdType     AdType            // advertisement type
+    PosterType PosterType        // advertiser type
+    Attributes map[string]string // attribute filters by attribute name, e.g. `{"cars.year": "2015"}`
+    SortType   SortType          // result order
+    Page       uint              // page number, starting from zero
+    Size       uint              // page size, up to `MaxPageSize`
+}
+
+// searchParameter maps a query parameter to its element in the `ad:ads-search-options` block of a response
+type searchParameter struct {
+    param   string
+    element string
+    get     func(q *SearchQuery) string
+    set     func(q *SearchQuery, value string) error
+}
+
+var searchParameters = []searchParameter{
+    {"q", "ad:q",
+        func(q *SearchQuery) string { return q.Keyword },
+        func(q *SearchQuery, v string) error { q.Keyword = v; return nil }},
+    {"categoryId", "ad:category-id",
+        func(q *SearchQuery) string { return formatUint(q.CategoryID) },
+        func(q *SearchQuery, v string) (err error) { q.CategoryID, err = u.ConvString2Uint(v, nil); return }},
+    {"locationId", "ad:location-id",
+        func(q *SearchQuery) string { return formatUint(q.LocationID) },
+        func(q *SearchQuery, v string) (err error) { q.LocationID, err = u.ConvString2Uint(v, nil); return }},
+    {"distance", "ad:distance",
+        func(q *SearchQuery) string { return formatUint(q.Distance) },
+        func(q *SearchQuery, v string) (err error) { q.Distance, err = u.ConvString2Uint(v, nil); return }},
+    {"minPrice", "ad:min-price",
+        func(q *SearchQuery) string { return formatUint(q.MinPrice) },
+        func(q *SearchQuery, v string) (err error) { q.MinPrice, err = u.ConvString2Uint(v, nil); return }},
+    {"maxPrice", "ad:max-price",
+        func(q *SearchQuery) string { return formatUint(q.MaxPrice) },
+        func(q *SearchQuery, v string) (err error) { q.MaxPrice, err = u.ConvString2Uint(v, nil); return }},
+    {"adType", "ad:ad-type",
+        func(q *SearchQuery) string { return string(q.AdType) },
+        func(q *SearchQuery, v string) error { q.AdType = AdType(v); return nil }},
+    {"posterType", "ad:poster-type",
+        func(q *SearchQuery) string { return string(q.PosterType) },
+        func(q *SearchQuery, v string) error { q.PosterType = PosterType(v); return nil }},
+    {"sortType", "ad:sort-type",
+        func(q *SearchQuery) string { return string(q.SortType) },
+        func(q *SearchQuery, v string) error { q.SortType = SortType(v); return nil }},
+    {"page", "ad:page",
+        func(q *SearchQuery) string { return formatUint(q.Page) },
+        func(q *SearchQuery, v string) (err error) { q.Page, err = u.ConvString2Uint(v, nil); return }},
+    {"size", "ad:size",
+        func(q *SearchQuery) string { return formatUint(q.Size) },
+        func(q *SearchQuery, v string) (err error) { q.Size, err = u.ConvString2Uint(v, nil); return }},
+}
+
+func formatUint(value uint) string {
+    if value == 0 {
+        return ""
+    }
+
+    return strconv.FormatUint(uint64(value), 10)
+}
+
+// Validate checks the query for values the API would reject
+func (q SearchQuery) Validate() error {
+    switch q.AdType {
+    case "", AdTypeOffered, AdTypeWanted:
+    default:
+        return fmt.Errorf("invalid ad type %q", q.AdType)
+    }
+
+    switch q.PosterType {
+    case "", PosterTypePrivate, PosterTypeCommercial:
+    default:
+        return fmt.Errorf("invalid poster type %q", q.PosterType)
+    }
+
+    switch q.SortType {
+    case "", SortDateDescending, SortDateAscending, SortPriceAscending, SortPriceDescending, SortDistanceAscending:
+    default:
+        return fmt.Errorf("invalid sort type %q", q.SortType)
+    }
+
+    if q.Distance > 0 && q.LocationID == 0 {
+        return fmt.Errorf("distance requires a location")
+    }
+
+    if q.SortType == SortDistanceAscending && q.LocationID == 0 {
+        return fmt.Errorf("sorting by distance requires a location")
+    }
+
+    if q.MaxPrice > 0 && q.MinPrice > q.MaxPrice {
+        return fmt.Errorf("minimum price %d exceeds maximum price %d", q.MinPrice, q.MaxPrice)
+    }
+
+    if q.Size > MaxPageSize {
+        return fmt.Errorf("page size %d exceeds %d", q.Size, MaxPageSize)
+    }
+
+    for name := range q.Attributes {
+        if strings.TrimSpace(name) == "" {
+            return fmt.Errorf("attribute filter without name")
+        }
+    }
+
+    return nil
+}
+
+// Values returns the query parameters of the search
+func (q SearchQuery) Values() url.Values {
+    values := url.Values{}
+
+    for _, p := range searchParameters {
+        if value := p.get(&q); value != "" {
+            values.Set(p.param, value)
+        }
+    }
+
+    for name, value := range q.Attributes {
+        values.Set("attr[" + name + "]", value)
+    }
+
+    return values
+}
+
+// Encode returns the URL-encoded query string of the search, sorted by parameter name
+func (q SearchQuery) Encode() string {
+    return q.Values().Encode()
+}
+
+// Path validates the query and returns the endpoint path of the search
+func (q SearchQuery) Path() (string, error) {
+    if err := q.Validate(); err != nil {
+        return "", err
+    }
+
+    if query := q.Encode(); query != "" {
+        return "/ads?" + query, nil
+    }
+
+    return "/ads", nil
+}
+
+// WithPage returns a copy of the query requesting another page
+func (q SearchQuery) WithPage(page uint) SearchQuery {
+    q.Page = page
+    return q
+}
+
+// ParseSearchQuery is to build a SearchQuery from the `ad:ads-search-options` block of a search response,
+// which reflects the parameters the API applied to the search
+func ParseSearchQuery(doc *etree.Document) (*SearchQuery, error) {
+    if doc == nil || doc.Root() == nil {
+        return nil, fmt.Errorf("empty API response")
+    }
+
+    options := doc.Root().FindElement("./ad:ads-search-options")
+    if options == nil {
+        return nil, fmt.Errorf("search options missing from API response")
+    }
+
+    query := &SearchQuery{}
+
+    for _, p := range searchParameters {
+        if value, err := u.ExtractText(options, "./" + p.element); err == nil && strings.TrimSpace(value) != "" {
+            if err := p.set(query, strings.TrimSpace(value)); err != nil {
+                return nil, fmt.Errorf("invalid search option %s: %v", p.element, err)
+            }
+        }
+    }
+
+    for _, attr := range options.FindElements("./attr:attributes/attr:attribute") {
+        name, err := u.ExtractAttrByTag(attr, "name")
+        if err != nil {
+            return nil, fmt.Errorf("search option attr:attribute without name")
+        }
+
+        value, _ := u.ExtractText(attr, "./attr:value")
+
+        if query.Attributes == nil {
+            query.Attributes = make(map[string]string)
+        }
+
+        query.Attributes[name] = value
+    }
+
+    return query, nil
+}
+
+// SearchOptions builds the `ad:ads-search-options` block describing the query, the inverse of ParseSearchQuery
+func (q SearchQuery) SearchOptions() *etree.Element {
+    options := etree.NewElement("ad:ads-search-options")
+
+    for _, p := range searchParameters {
+        if value := p.get(&q); value != "" {
+            options.CreateElement(p.element).SetText(value)
+        }
+    }
+
+    if len(q.Attributes) > 0 {
+        attrs := options.CreateElement("attr:attributes")
+
+        names := make([]string, 0, len(q.Attributes))
+        for name := range q.Attributes {
+            names = append(names, name)
+        }
+        sort.Strings(names)
+
+        for _, name := range names {
+            attr := attrs.CreateElement("attr:attribute")
+            attr.CreateAttr("name", name)
+            attr.CreateElement("attr:value").SetText(q.Attributes[name])
+        }
+    }
+
+    return options
+}
+
+// RequestSearch requests the advertisements matching the search query
+func (agent Agent) RequestSearch(ctx context.Context, query SearchQuery) (*etree.Document, error) {
+    path, err := query.Path()
+    if err != nil {
+        return nil, fmt.Errorf("ecg: invalid search query: %w", err)
+    }
+
+    return agent.RequestEndpointContext(ctx, path)
+}
@@ -0,0 +1,271 @@
+package ecg_test
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "github.com/GreenVine/ebay-classifieds-api"
+    "github.com/GreenVine/ebay-classifieds-api/parsers"
+    auparser "github.com/GreenVine/ebay-classifieds-api/parsers/au"
+    aumodels "github.com/GreenVine/ebay-classifieds-api/parsers/au/models"
+    "github.com/beevik/etree"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "sync"
+    "testing"
+)
+
+func TestSearchQueryPath(t *testing.T) {
+    query := ecg.SearchQuery{
+        Keyword:    "mountain bike & helmet",
+        CategoryID: 18560,
+        LocationID: 3003435,
+        Distance:   25,
+        MaxPrice:   500,
+        AdType:     ecg.AdTypeOffered,
+        Attributes: map[string]string{"bikes.type": "mountain"},
+        SortType:   ecg.SortPriceAscending,
+        Size:       50,
+    }
+
+    path, err := query.Path()
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    expected := "/ads?adType=OFFERED&attr%5Bbikes.type%5D=mountain&categoryId=18560&distance=25&locationId=3003435" +
+        "&maxPrice=500&q=mountain+bike+%26+helmet&size=50&sortType=PRICE_ASCENDING"
+
+    if path != expected {
+        t.Errorf("expected path %s, got %s", expected, path)
+    }
+
+    for _, invalid := range []ecg.SearchQuery{
+        {Distance: 10},
+        {MinPrice: 100, MaxPrice: 50},
+        {Size: ecg.MaxPageSize + 1},
+        {AdType: "SOLD"},
+    } {
+        if _, err := invalid.Path(); err == nil {
+            t.Errorf("expected query %+v to be invalid", invalid)
+        }
+    }
+}
+
+func TestSearchQueryRoundTrip(t *testing.T) {
+    query := ecg.SearchQuery{
+        Keyword:    "sofa",
+        CategoryID: 20045,
+        PosterType: ecg.PosterTypePrivate,
+        Attributes: map[string]string{"furniture.colour": "grey", "furniture.seats": "3"},
+        Page:       2,
+        Size:       20,
+    }
+
+    doc := etree.NewDocument()
+    root := doc.CreateElement("ad:ads")
+    root.AddChild(query.SearchOptions())
+
+    parsed, err := ecg.ParseSearchQuery(doc)
+    if err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    if !reflect.DeepEqual(*parsed, query) {
+        t.Errorf("expected %+v, got %+v", query, *parsed)
+    }
+}
+
+// searchPageXML renders a search response page with the given advertisement IDs
+func searchPageXML(page uint, size uint, total uint, ids ...uint) string {
+    doc := etree.NewDocument()
+    root := doc.CreateElement("ad:ads")
+    root.CreateAttr("xmlns:ad", "http://www.ebayclassifiedsgroup.com/schema/ad/v1")
+    root.CreateAttr("xmlns:types", "http://www.ebayclassifiedsgroup.com/schema/types/v1")
+
+    for _, id := range ids {
+        ad := root.CreateElement("ad:ad")
+        ad.CreateAttr("id", fmt.Sprint(id))
+        ad.CreateElement("ad:title").SetText(fmt.Sprintf("Advert %d", id))
+    }
+
+    root.AddChild(ecg.SearchQuery{Page: page, Size: size}.SearchOptions())
+    root.CreateElement("types:paging").CreateElement("types:numFound").SetText(fmt.Sprint(total))
+
+    xml, _ := doc.WriteToString()
+    return xml
+}
+
+func TestAgentSearchAll(t *testing.T) {
+    pages := map[string]string{
+        "":  searchPageXML(0, 2, 5, 1, 2),
+        "1": searchPageXML(1, 2, 5, 2, 3), // advert 2 shifted onto the second page
+        "2": searchPageXML(2, 2, 5, 4),
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(pages[r.URL.Query().Get("page")]))
+    }))
+    defer server.Close()
+
+    agent := ecg.Agent{Endpoint: server.URL}
+
+    for _, prefetch := range []bool{false, true} {
+        cursor := agent.SearchAll(context.Background(), ecg.SearchQuery{Keyword: "bike", Size: 2})
+        cursor.Prefetch = prefetch
+
+        var ids []uint
+        for cursor.Next() {
+            ids = append(ids, cursor.Advert().ID)
+        }
+        cursor.Close()
+
+        if err := cursor.Err(); err != nil {
+            t.Fatalf("unexpected error: %v", err)
+        }
+
+        if !reflect.DeepEqual(ids, []uint{1, 2, 3, 4}) {
+            t.Errorf("prefetch %v: expected adverts 1 to 4, got %v", prefetch, ids)
+        }
+    }
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cursor := agent.SearchAll(ctx, ecg.SearchQuery{Size: 2})
+
+    if !cursor.Next() {
+        t.Fatalf("expected first advert, got %v", cursor.Err())
+    }
+
+    cancel()
+
+    if cursor.Next() || !errors.Is(cursor.Err(), context.Canceled) {
+        t.Errorf("expected iteration to stop on cancellation, got %v", cursor.Err())
+    }
+}
+
+// searchAllIDs iterates over all advertisements of a search, returning their IDs
+func searchAllIDs(t *testing.T, agent ecg.Agent, query ecg.SearchQuery) []uint {
+    cursor := agent.SearchAll(context.Background(), query)
+    defer cursor.Close()
+
+    var ids []uint
+    for cursor.Next() {
+        ids = append(ids, cursor.Advert().ID)
+    }
+
+    if err := cursor.Err(); err != nil {
+        t.Fatalf("unexpected error: %v", err)
+    }
+
+    return ids
+}
+
+func TestAgentSearchAllSkippedAdverts(t *testing.T) {
+    pages := map[string]string{
+        "":  strings.Replace(searchPageXML(0, 2, 4, 0, 0), `id="0"`, `id="unknown"`, -1), // both adverts skipped by the parser
+        "1": searchPageXML(1, 2, 4, 3, 4),
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(pages[r.URL.Query().Get("page")]))
+    }))
+    defer server.Close()
+
+    if ids := searchAllIDs(t, ecg.Agent{Endpoint: server.URL}, ecg.SearchQuery{Size: 2}); !reflect.DeepEqual(ids, []uint{3, 4}) {
+        t.Errorf("expected adverts 3 and 4, got %v", ids)
+    }
+}
+
+// unpaginatedParser parses pages of advertisements without pagination
+type unpaginatedParser struct {
+    auparser.Parser
+}
+
+func (p unpaginatedParser) ParseAdvertList(doc *etree.Document) (interface{}, *parsers.ParseReport) {
+    model, report := p.Parser.ParseAdvertList(doc)
+    if category, ok := model.(*aumodels.Category); ok {
+        category.Pagination = nil
+    }
+
+    return model, report
+}
+
+func TestAgentSearchAllUnknownEntries(t *testing.T) {
+    parsers.Register("unpaginated", unpaginatedParser{})
+
+    var mu sync.Mutex
+    var requests int
+
+    pages := map[string]string{
+        "":  searchPageXML(0, 2, 0, 1, 2), // no number of matched entries
+        "1": searchPageXML(1, 2, 0, 3),
+        "2": searchPageXML(2, 2, 0),
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        requests++
+        mu.Unlock()
+
+        w.Write([]byte(pages[r.URL.Query().Get("page")]))
+    }))
+    defer server.Close()
+
+    for _, marketplace := range []string{"", "unpaginated"} {
+        requests = 0
+        agent := ecg.Agent{Endpoint: server.URL, Marketplace: marketplace}
+
+        if ids := searchAllIDs(t, agent, ecg.SearchQuery{Size: 2}); !reflect.DeepEqual(ids, []uint{1, 2, 3}) {
+            t.Errorf("marketplace %q: expected adverts 1 to 3, got %v", marketplace, ids)
+        }
+
+        if requests != 3 {
+            t.Errorf("marketplace %q: expected pages to be fetched until an empty one, got %d requests", marketplace, requests)
+        }
+    }
+}
+
+func TestAgentSearchAllCappedPageSize(t *testing.T) {
+    pages := map[string]string{ // the API serves pages of 2 adverts although 5 were requested
+        "":  searchPageXML(0, 2, 5, 1, 2),
+        "1": searchPageXML(1, 2, 5, 3, 4),
+        "2": searchPageXML(2, 2, 5, 5),
+    }
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte(pages[r.URL.Query().Get("page")]))
+    }))
+    defer server.Close()
+
+    if ids := searchAllIDs(t, ecg.Agent{Endpoint: server.URL}, ecg.SearchQuery{Size: 5}); !reflect.DeepEqual(ids, []uint{1, 2, 3, 4, 5}) {
+        t.Errorf("expected adverts 1 to 5, got %v", ids)
+    }
+}
+
+func TestAgentSearchAllRepeatedLastPage(t *testing.T) {
+    var mu sync.Mutex
+    var requests int
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        mu.Lock()
+        requests++
+        mu.Unlock()
+
+        if r.URL.Query().Get("page") == "" {
+            w.Write([]byte(searchPageXML(0, 2, 0, 1, 2)))
+        } else { // pages out of range repeat the last page
+            w.Write([]byte(searchPageXML(1, 2, 0, 3)))
+        }
+    }))
+    defer server.Close()
+
+    if ids := searchAllIDs(t, ecg.Agent{Endpoint: server.URL}, ecg.SearchQuery{Size: 2}); !reflect.DeepEqual(ids, []uint{1, 2, 3}) {
+        t.Errorf("expected adverts 1 to 3, got %v", ids)
+    }
+
+    if requests != 3 {
+        t.Errorf("expected the iteration to stop at the first repeated page, got %d requests", requests)
+    }
+}
@@ -0,0 +1,36 @@
+package ecg
+
+import (
+    "net"
+    "net/http"
+    "time"
+)
+
+// DefaultTransport is the pooled transport shared by every agent without its own HTTP client or transport,
+// so that connections to the API endpoint are kept alive and reused across requests.
+var DefaultTransport http.RoundTripper = &http.Transport{
+    Proxy: http.ProxyFromEnvironment,
+    DialContext: (&net.Dialer{
+        Timeout:   30 * time.Second,
+        KeepAlive: 30 * time.Second,
+    }).DialContext,
+    MaxIdleConns:          100,
+    MaxIdleConnsPerHost:   16,
+    IdleConnTimeout:       90 * time.Second,
+    TLSHandshakeTimeout:   10 * time.Second,
+    ExpectContinueTimeout: 1 * time.Second,
+}
+
+// httpClient returns the HTTP client used by the agent.
+// `HTTPClient` takes precedence over `Transport`, and the pooled default is used if neither is set.
+func (agent Agent) httpClient() *http.Client {
+    if agent.HTTPClient != nil {
+        return agent.HTTPClient
+    }
+
+    if agent.Transport != nil {
+        return &http.Client{Transport: agent.Transport}
+    }
+
+    return &http.Client{Transport: DefaultTransport}
+}
@@ -1,11 +1,19 @@
 package utils
 
+import "github.com/GreenVine/ebay-classifieds-api/money"
+
+// ErrorReporter receives the error of a field that was replaced with a fallback value,
+// identified by its path in the response, e.g. `ads/ad/price/amount`
+type ErrorReporter interface {
+    Report(path string, err error)
+}
+
 // FallbackUintWithReport is to replace the variable with the fallback value if an error exists
-// and append the error to the array
-func FallbackUintWithReport(value uint, err error) func(fallback uint, errArray *[]error, displayError error) uint {
-    return func(fallback uint, errArray *[]error, displayError error) uint {
+// and report the error
+func FallbackUintWithReport(value uint, err error) func(fallback uint, reporter ErrorReporter, path string) uint {
+    return func(fallback uint, reporter ErrorReporter, path string) uint {
         if err != nil { // fallback is necessary
-            *errArray = append(*errArray, displayError)
+            reporter.Report(path, err)
             return fallback
         }
 
@@ -14,11 +22,11 @@ func FallbackUintWithReport(value uint, err error) func(fallback uint, errArray
 }
 
 // FallbackFloat64WithReport is to replace the variable with the fallback value if an error exists
-// and append the error to the array
-func FallbackFloat64WithReport(value float64, err error) func(fallback float64, errArray *[]error, displayError error) float64 {
-    return func(fallback float64, errArray *[]error, displayError error) float64 {
+// and report the error
+func FallbackFloat64WithReport(value float64, err error) func(fallback float64, reporter ErrorReporter, path string) float64 {
+    return func(fallback float64, reporter ErrorReporter, path string) float64 {
         if err != nil { // fallback is necessary
-            *errArray = append(*errArray, displayError)
+            reporter.Report(path, err)
             return fallback
         }
 
@@ -27,11 +35,24 @@ func FallbackFloat64WithReport(value float64, err error) func(fallback float64,
 }
 
 // FallbackStringWithReport is to replace the variable with the fallback value if an error exists
-// and append the error to the array
-func FallbackStringWithReport(value string, err error) func(fallback string, errArray *[]error, displayError error) string {
-    return func(fallback string, errArray *[]error, displayError error) string {
+// and report the error
+func FallbackStringWithReport(value string, err error) func(fallback string, reporter ErrorReporter, path string) string {
+    return func(fallback string, reporter ErrorReporter, path string) string {
+        if err != nil { // fallback is necessary
+            reporter.Report(path, err)
+            return fallback
+        }
+
+        return value // return actual value as no error occurs
+    }
+}
+
+// FallbackAmountWithReport is to replace the variable with the fallback value if an error exists
+// and report the error
+func FallbackAmountWithReport(value money.Amount, err error) func(fallback money.Amount, reporter ErrorReporter, path string) money.Amount {
+    return func(fallback money.Amount, reporter ErrorReporter, path string) money.Amount {
         if err != nil { // fallback is necessary
-            *errArray = append(*errArray, displayError)
+            reporter.Report(path, err)
             return fallback
         }
 
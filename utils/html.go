@@ -3,21 +3,227 @@ package utils
 import (
     "encoding/base64"
     "github.com/jaytaylor/html2text"
+    "golang.org/x/net/html"
+    "io"
     "strings"
+    "unicode"
 )
 
-// FormatHTML2Text converts HTML to plain text
+// DefaultAllowedTags are the tags kept by SanitizeHTML unless others are given, all of them without attributes
+var DefaultAllowedTags = []string{"p", "br", "b", "strong", "i", "em", "u", "ul", "ol", "li"}
+
+// droppedTags are removed by SanitizeHTML along with their content, even if allowed
+var droppedTags = map[string]bool{
+    "script": true, "style": true, "iframe": true, "object": true, "embed": true,
+    "noscript": true, "template": true, "head": true, "svg": true, "math": true,
+}
+
+// voidTags have no content and no end tag
+var voidTags = map[string]bool{"br": true, "hr": true, "wbr": true, "img": true}
+
+// blockTags start a new line in plain text
+var blockTags = map[string]bool{
+    "p": true, "div": true, "br": true, "hr": true, "li": true, "ul": true, "ol": true, "tr": true, "table": true,
+    "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "blockquote": true, "pre": true,
+}
+
+// impliedEndTags lists the open tags implicitly closed by a start tag, e.g. a list item ends the previous one
+var impliedEndTags = map[string]map[string]bool{
+    "li": {"li": true, "p": true}, "dt": {"dt": true, "dd": true, "p": true}, "dd": {"dt": true, "dd": true, "p": true},
+    "tr": {"tr": true, "td": true, "th": true}, "td": {"td": true, "th": true}, "th": {"td": true, "th": true},
+    "p": {"p": true}, "div": {"p": true}, "ul": {"p": true}, "ol": {"p": true}, "dl": {"p": true}, "hr": {"p": true},
+    "table": {"p": true}, "blockquote": {"p": true}, "pre": {"p": true},
+    "h1": {"p": true}, "h2": {"p": true}, "h3": {"p": true}, "h4": {"p": true}, "h5": {"p": true}, "h6": {"p": true},
+}
+
+// scopeTags bound the tags implicitly closed by a start tag, e.g. an item of a nested list does not end the outer item
+var scopeTags = map[string]bool{
+    "ul": true, "ol": true, "dl": true, "table": true, "tr": true, "td": true, "th": true, "blockquote": true,
+}
+
+// FormatHTML2Text converts HTML to plain UTF-8 text
 func FormatHTML2Text(html string) (string, error) {
     html = strings.Replace(html, "\r\n", "<br />", -1)
     html = strings.Replace(html, "\n", "<br />", -1)
-    return html2text.FromString(html, html2text.Options{ PrettyTables: true })
+
+    text, err := html2text.FromString(html, html2text.Options{ PrettyTables: true })
+    if err != nil {
+        return "", err
+    }
+
+    return strings.ToValidUTF8(text, "\uFFFD"), nil
+}
+
+// ExtractHTMLText converts HTML to clean UTF-8 plain text, keeping only the text and the line breaks of block elements.
+// Unlike FormatHTML2Text, link targets and emphasis markers are left out, and scripts and styles are removed.
+func ExtractHTMLText(source string) (string, error) {
+    var text strings.Builder
+    dropped := 0 // depth within dropped elements
+
+    tokenizer := html.NewTokenizer(strings.NewReader(source))
+
+    for {
+        tokenType := tokenizer.Next()
+
+        switch tokenType {
+        case html.ErrorToken:
+            if err := tokenizer.Err(); err != io.EOF {
+                return "", err
+            }
+
+            return cleanLines(text.String()), nil
+        case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+            name, _ := tokenizer.TagName()
+            tag := string(name)
+
+            if droppedTags[tag] {
+                if tokenType == html.StartTagToken && !voidTags[tag] {
+                    dropped++
+                } else if tokenType == html.EndTagToken && dropped > 0 {
+                    dropped--
+                }
+            } else if dropped == 0 && blockTags[tag] {
+                text.WriteString("\n")
+            }
+        case html.TextToken:
+            if dropped == 0 {
+                text.WriteString(strings.Map(func(r rune) rune { // line breaks only come from block elements
+                    if unicode.IsSpace(r) {
+                        return ' '
+                    }
+
+                    return r
+                }, string(tokenizer.Text())))
+            }
+        }
+    }
+}
+
+// cleanLines trims every line, removes empty lines and replaces invalid UTF-8
+func cleanLines(text string) string {
+    var lines []string
+
+    for _, line := range strings.Split(text, "\n") {
+        if line = strings.Join(strings.Fields(line), " "); line != "" {
+            lines = append(lines, line)
+        }
+    }
+
+    return strings.ToValidUTF8(strings.Join(lines, "\n"), "\uFFFD")
 }
 
-// FormatHTML2Base64 converts HTML to base64-decoded plain text
+// FormatHTML2Base64 converts HTML to base64-encoded plain text
 func FormatHTML2Base64(html string) (string, error) {
-    if text, err := FormatHTML2Text(html); err == nil {
-        return base64.StdEncoding.EncodeToString([]byte(text)), nil
+    text, err := FormatHTML2Text(html)
+    if err != nil {
+        return "", err
+    }
+
+    return base64.StdEncoding.EncodeToString([]byte(text)), nil
+}
+
+// SanitizeHTML keeps the allowed tags of HTML without any of their attributes, which strips links, images and styling,
+// and removes scripts, styles and other embedded content entirely. Tags left open are closed where HTML implies it,
+// e.g. a list item by the next one, or at the end.
+// If no tags are given, `DefaultAllowedTags` are kept.
+func SanitizeHTML(source string, allowedTags []string) (string, error) {
+    if allowedTags == nil {
+        allowedTags = DefaultAllowedTags
+    }
+
+    allowed := make(map[string]bool, len(allowedTags))
+    for _, tag := range allowedTags {
+        allowed[strings.ToLower(tag)] = true
+    }
+
+    var sanitized strings.Builder
+    var open []string // allowed tags that are not closed yet
+    dropped := 0      // depth within dropped elements
+
+    tokenizer := html.NewTokenizer(strings.NewReader(source))
+
+    for {
+        tokenType := tokenizer.Next()
+
+        switch tokenType {
+        case html.ErrorToken:
+            if err := tokenizer.Err(); err != io.EOF {
+                return "", err
+            }
+
+            for i := len(open) - 1; i >= 0; i-- {
+                sanitized.WriteString("</" + open[i] + ">")
+            }
+
+            return sanitized.String(), nil
+        case html.StartTagToken, html.SelfClosingTagToken:
+            name, _ := tokenizer.TagName()
+            tag := string(name)
+
+            if droppedTags[tag] {
+                if tokenType == html.StartTagToken && !voidTags[tag] {
+                    dropped++
+                }
+            } else if dropped == 0 {
+                open = closeImplied(&sanitized, open, tag)
+
+                if allowed[tag] {
+                    sanitized.WriteString("<" + tag + ">")
+
+                    if tokenType == html.StartTagToken && !voidTags[tag] {
+                        open = append(open, tag)
+                    }
+                }
+            }
+        case html.EndTagToken:
+            name, _ := tokenizer.TagName()
+            tag := string(name)
+
+            if droppedTags[tag] {
+                if dropped > 0 {
+                    dropped--
+                }
+            } else if dropped == 0 && allowed[tag] {
+                for i := len(open) - 1; i >= 0; i-- { // close the tag along with the tags left open within it
+                    if open[i] == tag {
+                        open = closeOpen(&sanitized, open, i)
+                        break
+                    }
+                }
+            }
+        case html.TextToken:
+            if dropped == 0 {
+                sanitized.WriteString(html.EscapeString(strings.ToValidUTF8(string(tokenizer.Text()), "\uFFFD")))
+            }
+        }
+    }
+}
+
+// closeImplied closes the open tags implicitly ended by a start tag along with the tags left open within them,
+// searching up to the nearest scope tag
+func closeImplied(sanitized *strings.Builder, open []string, tag string) []string {
+    closed := impliedEndTags[tag]
+    if closed == nil {
+        return open
+    }
+
+    from := len(open)
+    for i := len(open) - 1; i >= 0; i-- {
+        if closed[open[i]] {
+            from = i
+        } else if scopeTags[open[i]] {
+            break
+        }
+    }
+
+    return closeOpen(sanitized, open, from)
+}
+
+// closeOpen writes the end tags of the open tags from an index on, returning the tags still open
+func closeOpen(sanitized *strings.Builder, open []string, from int) []string {
+    for i := len(open) - 1; i >= from; i-- {
+        sanitized.WriteString("</" + open[i] + ">")
     }
 
-    return "", nil
+    return open[:from]
 }
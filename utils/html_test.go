@@ -0,0 +1,52 @@
+package utils_test
+
+import (
+    u "github.com/GreenVine/ebay-classifieds-api/utils"
+    "testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+    tests := []struct {
+        html      string
+        allowed   []string
+        sanitized string
+    }{
+        {`<p>Great <b class="x">bike</b><br/>Pick up only</p>`, nil, `<p>Great <b>bike</b><br>Pick up only</p>`},
+        {`Call <a href="javascript:alert(1)">me</a>`, nil, `Call me`},
+        {`<script>alert("x")</script><style>p{}</style>Text`, nil, `Text`},
+        {`<script>alert("x")</script>`, []string{"script"}, ``},
+        {`<img src=x onerror=alert(1)>`, nil, ``},
+        {`<ul><li>one<li>two`, nil, `<ul><li>one</li><li>two</li></ul>`},
+        {`<ul><li>one<ul><li>nested</ul><li>two</ul>`, nil, `<ul><li>one<ul><li>nested</li></ul></li><li>two</li></ul>`},
+        {`<p>one<p>two<ul><li><b>three</ul>`, nil, `<p>one</p><p>two</p><ul><li><b>three</b></li></ul>`},
+        {`<p>one<div>two</div>`, nil, `<p>one</p>two`},
+        {`<b><i>bold</b> &amp; &lt;escaped&gt;`, nil, `<b><i>bold</i></b> &amp; &lt;escaped&gt;`},
+        {`<p>Kept <em>only</em> paragraphs</p>`, []string{"p"}, `<p>Kept only paragraphs</p>`},
+        {`</p>Stray end tag`, nil, `Stray end tag`},
+    }
+
+    for _, test := range tests {
+        sanitized, err := u.SanitizeHTML(test.html, test.allowed)
+        if err != nil || sanitized != test.sanitized {
+            t.Errorf("SanitizeHTML(%q): expected %q, got %q: %v", test.html, test.sanitized, sanitized, err)
+        }
+    }
+}
+
+func TestExtractHTMLText(t *testing.T) {
+    tests := []struct {
+        html string
+        text string
+    }{
+        {"<p>Great <b>bike</b>,\n  barely   used</p><p>Pick up only</p>", "Great bike, barely used\nPick up only"},
+        {"See <a href=\"https://example.com\">photos</a>.<br/><br/>Thanks", "See photos.\nThanks"},
+        {"<ul><li>One</li><li>Two</li></ul><script>alert(1)</script><style>p{}</style>", "One\nTwo"},
+        {"Caf\xe9 &amp; bar &lt;3", "Caf\uFFFD & bar <3"},
+    }
+
+    for _, test := range tests {
+        if text, err := u.ExtractHTMLText(test.html); err != nil || text != test.text {
+            t.Errorf("ExtractHTMLText(%q): expected %q, got %q: %v", test.html, test.text, text, err)
+        }
+    }
+}
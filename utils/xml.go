@@ -1,10 +1,18 @@
 package utils
 
 import (
+    "errors"
     "fmt"
+    "github.com/GreenVine/ebay-classifieds-api/money"
     "github.com/beevik/etree"
 )
 
+// Errors of elements and attributes missing from a response
+var (
+    ErrElementMissing   = errors.New("element invalid or does not exist")
+    ErrAttributeMissing = errors.New("attribute invalid or does not exist")
+)
+
 // ParseXML will parse XML responses and convert it to JSON
 func ParseXML(rawXML string) (*etree.Document, error) {
     doc := etree.NewDocument()
@@ -24,7 +32,7 @@ func ExtractText(element *etree.Element, path string) (string, error) {
         }
     }
 
-    return "", fmt.Errorf("element invalid or does not exist")
+    return "", ErrElementMissing
 }
 
 // ExtractTextAsUint wraps ExtractText and converts result to uint
@@ -37,6 +45,16 @@ func ExtractTextAsFloat64(element *etree.Element, path string) (float64, error)
     return ConvString2Float64(ExtractText(element, path))
 }
 
+// ExtractTextAsAmount wraps ExtractText and parses the result as an exact amount of a currency
+func ExtractTextAsAmount(element *etree.Element, path string, currency string) (money.Amount, error) {
+    text, err := ExtractText(element, path)
+    if err != nil {
+        return money.Amount{}, err
+    }
+
+    return money.Parse(text, currency)
+}
+
 // ExtractAttrByTag extracts a given tag from attributes
 func ExtractAttrByTag(element *etree.Element, tag string) (string, error) {
     if element != nil {
@@ -45,5 +63,5 @@ func ExtractAttrByTag(element *etree.Element, tag string) (string, error) {
         }
     }
 
-    return "", fmt.Errorf("attribute invalid or does not exist")
+    return "", ErrAttributeMissing
 }